@@ -26,7 +26,10 @@ CREATE TABLE `t_auction`  (
   `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片归属账户地址',
   `token_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片Token ID',
   `weight` int(0) NOT NULL DEFAULT 0 COMMENT '拍卖百分比 (整数表示，如 50 表示 50%)',
-  `price` int(0) NOT NULL DEFAULT 0 COMMENT '百分比单价 (单位：最小货币单位，如分)',
+  `price` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '百分比单价（PXC最小单位）',
+  `listed_weight` int(0) NOT NULL DEFAULT 0 COMMENT '挂牌时的份额（签名中的weight）',
+  `nonce` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '挂牌签名nonce',
+  `signature` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '卖家EIP-712挂牌签名',
   `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
   `updated_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) ON UPDATE CURRENT_TIMESTAMP(0) COMMENT '更新时间',
   PRIMARY KEY (`id`) USING BTREE,
@@ -48,19 +51,63 @@ CREATE TABLE `t_auction_his`  (
   `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '卖家地址',
   `token_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片Token ID',
   `weight` bigint(0) NOT NULL COMMENT '拍卖百分比 (历史记录，使用 BIGINT)',
-  `price` bigint(0) NOT NULL COMMENT '百分比单价 (历史记录，使用 BIGINT)',
+  `price` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '百分比单价（PXC最小单位）',
+  `pay_tx_hash` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'PXC付款交易哈希（旧版分步交割）',
+  `tx_hash` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '结算交易哈希',
+  `creator` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '版税收款人（创作者）地址',
+  `royalty` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '0' COMMENT '版税金额（PXC最小单位）',
+  `royalty_tx_hash` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '版税付款交易哈希（旧版分步交割）',
+  `nonce` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '订单签名nonce',
+  `signature` varchar(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '买家EIP-712订单签名',
+  `block_number` bigint(0) UNSIGNED NOT NULL DEFAULT 0 COMMENT '交易所在区块',
+  `block_hash` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '交易所在区块哈希',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'pending' COMMENT '确认状态 pending/confirmed/failed',
   `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `idx_token_id`(`token_id`) USING BTREE,
   INDEX `idx_buyer`(`buyer`) USING BTREE,
   INDEX `idx_address`(`address`) USING BTREE,
-  INDEX `idx_created_at`(`created_at`) USING BTREE
+  INDEX `idx_creator`(`creator`) USING BTREE,
+  INDEX `idx_created_at`(`created_at`) USING BTREE,
+  INDEX `idx_status`(`status`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '拍卖历史记录表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of t_auction_his
 -- ----------------------------
 
+-- ----------------------------
+-- Table structure for t_chain_event
+-- ----------------------------
+DROP TABLE IF EXISTS `t_chain_event`;
+CREATE TABLE `t_chain_event`  (
+  `id` bigint(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `chain_id` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '链ID',
+  `contract` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '合约地址',
+  `event` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '事件名称 Transfer/Approval/ApprovalForAll',
+  `from_addr` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'Transfer的from，Approval/ApprovalForAll的owner',
+  `to_addr` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'Transfer的to，Approval的spender/approved，ApprovalForAll的operator',
+  `value` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'PXC金额或PXA tokenId (十进制字符串)',
+  `approved` tinyint(1) NOT NULL DEFAULT 0 COMMENT 'ApprovalForAll的授权状态',
+  `block_number` bigint(0) UNSIGNED NOT NULL COMMENT '区块高度',
+  `block_hash` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '区块哈希',
+  `tx_hash` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '交易哈希',
+  `log_index` int(0) UNSIGNED NOT NULL COMMENT '日志在区块中的序号',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'pending' COMMENT '确认状态 pending/confirmed',
+  `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_tx_log`(`chain_id`, `tx_hash`, `log_index`) USING BTREE,
+  INDEX `idx_contract_event`(`chain_id`, `contract`, `event`) USING BTREE,
+  INDEX `idx_from_addr`(`from_addr`) USING BTREE,
+  INDEX `idx_to_addr`(`to_addr`) USING BTREE,
+  INDEX `idx_block_number`(`block_number`) USING BTREE,
+  INDEX `idx_status`(`status`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '链上合约事件表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_chain_event
+-- ----------------------------
+
 -- ----------------------------
 -- Table structure for t_content
 -- ----------------------------
@@ -69,15 +116,25 @@ CREATE TABLE `t_content`  (
   `id` bigint(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
   `title` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '原图片名称',
   `content` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片保存路径',
+  `thumbnail` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '缩略图路径',
+  `preview` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '带水印的预览图路径',
   `content_hash` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片内容哈希值',
-  `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片上传用户地址',
+  `cid` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'IPFS CIDv1',
+  `phash` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '感知哈希(dHash)',
+  `mime_type` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '按文件内容识别的MIME类型',
+  `file_ext` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '保存文件扩展名',
+  `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片当前归属地址',
+  `creator` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '图片上传用户（创作者）地址',
+  `royalty_bps` int(0) NOT NULL DEFAULT 0 COMMENT '二次销售版税（万分比）',
   `token_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片Token ID',
+  `chain_id` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '铸造所在链ID',
   `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
   `updated_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) ON UPDATE CURRENT_TIMESTAMP(0) COMMENT '更新时间',
   PRIMARY KEY (`id`) USING BTREE,
   INDEX `idx_token_id`(`token_id`) USING BTREE,
   INDEX `idx_address`(`address`) USING BTREE,
-  INDEX `idx_content_hash`(`content_hash`) USING BTREE
+  INDEX `idx_content_hash`(`content_hash`) USING BTREE,
+  INDEX `idx_chain_id`(`chain_id`) USING BTREE
 ) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '数字资产（图片）信息表' ROW_FORMAT = Dynamic;
 
 -- ----------------------------
@@ -101,6 +158,121 @@ CREATE TABLE `t_equity_registration`  (
 -- Records of t_equity_registration
 -- ----------------------------
 
+-- ----------------------------
+-- Table structure for t_idempotency
+-- ----------------------------
+DROP TABLE IF EXISTS `t_idempotency`;
+CREATE TABLE `t_idempotency`  (
+  `id` bigint(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `idem_key` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '幂等键 (Idempotency-Key请求头)',
+  `address` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '请求用户地址',
+  `method` varchar(10) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '请求方法',
+  `path` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '请求路径',
+  `body_hash` char(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '查询参数和请求体的哈希',
+  `status` int(0) NOT NULL DEFAULT 0 COMMENT 'HTTP状态码，0表示处理中',
+  `response` mediumtext CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '首次响应内容',
+  `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_idem_key`(`idem_key`, `address`, `method`, `path`) USING BTREE,
+  INDEX `idx_created_at`(`created_at`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '幂等请求记录表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_idempotency
+-- ----------------------------
+
+-- ----------------------------
+-- Table structure for t_index_checkpoint
+-- ----------------------------
+DROP TABLE IF EXISTS `t_index_checkpoint`;
+CREATE TABLE `t_index_checkpoint`  (
+  `chain_id` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '链ID',
+  `contract` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '合约地址',
+  `block_number` bigint(0) UNSIGNED NOT NULL COMMENT '已索引到的区块高度',
+  `updated_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) ON UPDATE CURRENT_TIMESTAMP(0) COMMENT '更新时间',
+  PRIMARY KEY (`chain_id`, `contract`) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '事件索引检查点表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_index_checkpoint
+-- ----------------------------
+
+-- ----------------------------
+-- Table structure for t_moderation
+-- ----------------------------
+DROP TABLE IF EXISTS `t_moderation`;
+CREATE TABLE `t_moderation`  (
+  `id` bigint(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `title` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '原图片名称',
+  `content` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片保存路径',
+  `thumbnail` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '缩略图路径',
+  `preview` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '带水印的预览图路径',
+  `content_hash` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '图片内容哈希值',
+  `cid` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'IPFS CIDv1',
+  `phash` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '感知哈希(dHash)',
+  `mime_type` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '按文件内容识别的MIME类型',
+  `file_ext` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '保存文件扩展名',
+  `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '上传用户地址',
+  `creator` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '创作者地址',
+  `royalty_bps` int(0) NOT NULL DEFAULT 0 COMMENT '二次销售版税（万分比）',
+  `token_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '预分配的Token ID',
+  `chain_id` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '铸造所在链ID',
+  `similar_token_id` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '最相似的已登记Token ID',
+  `similarity` double NOT NULL COMMENT '感知哈希相似度(0~1)',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'pending' COMMENT '审核状态 pending/approved/rejected',
+  `reviewer` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '审核人地址',
+  `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
+  `updated_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) ON UPDATE CURRENT_TIMESTAMP(0) COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_chain_status`(`chain_id`, `status`) USING BTREE,
+  INDEX `idx_token_id`(`token_id`) USING BTREE,
+  INDEX `idx_content_hash`(`content_hash`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '上传审核队列表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_moderation
+-- ----------------------------
+
+-- ----------------------------
+-- Table structure for t_signature_nonce
+-- ----------------------------
+DROP TABLE IF EXISTS `t_signature_nonce`;
+CREATE TABLE `t_signature_nonce`  (
+  `id` bigint(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `chain_id` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '链ID',
+  `signer` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '签名者地址（小写）',
+  `kind` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '签名类型 listing/order',
+  `nonce` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '签名nonce（十进制）',
+  `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `uk_signer_nonce`(`chain_id`, `signer`, `kind`, `nonce`) USING BTREE
+) ENGINE = InnoDB AUTO_INCREMENT = 1 CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '已使用的EIP-712签名nonce表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_signature_nonce
+-- ----------------------------
+
+-- ----------------------------
+-- Table structure for t_upload
+-- ----------------------------
+DROP TABLE IF EXISTS `t_upload`;
+CREATE TABLE `t_upload`  (
+  `upload_id` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '上传ID',
+  `address` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '上传用户地址',
+  `file_name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '原始文件名',
+  `size` bigint(0) UNSIGNED NOT NULL COMMENT '文件总大小（字节）',
+  `received` bigint(0) UNSIGNED NOT NULL DEFAULT 0 COMMENT '已接收字节数',
+  `created_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) COMMENT '创建时间',
+  `updated_at` timestamp(0) NOT NULL DEFAULT CURRENT_TIMESTAMP(0) ON UPDATE CURRENT_TIMESTAMP(0) COMMENT '更新时间',
+  PRIMARY KEY (`upload_id`) USING BTREE,
+  INDEX `idx_address`(`address`) USING BTREE,
+  INDEX `idx_updated_at`(`updated_at`) USING BTREE
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci COMMENT = '分片上传会话表' ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_upload
+-- ----------------------------
+
 -- ----------------------------
 -- Table structure for t_user
 -- ----------------------------
@@ -0,0 +1,181 @@
+package configs
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// 配置文件路径，文件不存在时使用默认配置
+const CONFIG_FILE = "./config.json"
+
+// deploy子命令写入的合约地址登记文件，键为chainId，与config.json中的contracts合并，同一链ID以该文件为准
+const CONTRACTS_FILE = "./contracts.json"
+
+// 某条链上部署的合约地址
+type Contracts struct {
+	PXC20       string `json:"pxc20"`        //ERC20合约地址
+	PXA721      string `json:"pxa721"`       //ERC721合约地址
+	Settlement  string `json:"settlement"`   //份额交易结算合约地址
+	DeployBlock uint64 `json:"deploy_block"` //部署所在区块
+}
+
+// 一条链的连接配置
+type Chain struct {
+	Name      string `json:"name"`       //链名称，如 hardhat、consortium、sepolia
+	RPCURL    string `json:"rpc_url"`    //节点HTTP RPC地址
+	WSURL     string `json:"ws_url"`     //节点WebSocket RPC地址，事件订阅使用
+	Admin     string `json:"admin"`      //在该链上代付gas、发起交割的管理员用户名，缺省为admin
+	AdminPass string `json:"admin_pass"` //管理员keystore密码，缺省为1234
+}
+
+// 上传文件类别
+const (
+	UPLOAD_IMAGE = "image"
+	UPLOAD_AUDIO = "audio"
+	UPLOAD_VIDEO = "video"
+	UPLOAD_PDF   = "pdf"
+	UPLOAD_TEXT  = "text"
+)
+
+// 图片像素数（宽×高）缺省上限，按RGBA解码约占160MB内存
+const DEFAULT_MAX_PIXELS = 40000000
+
+// 某类上传文件的限制
+type UploadType struct {
+	MIME      []string `json:"mime"`       //允许的MIME类型（按文件内容识别）
+	MaxSize   int64    `json:"max_size"`   //文件大小上限（字节）
+	MaxPixels int64    `json:"max_pixels"` //图片像素数（宽×高）上限，仅image类别使用，0表示使用缺省上限
+}
+
+// 存储后端类型
+const (
+	STORAGE_LOCAL = "local"
+	STORAGE_S3    = "s3"
+)
+
+// 内容存储配置
+type Storage struct {
+	Type      string `json:"type"`       //local 或 s3
+	Dir       string `json:"dir"`        //本地存储目录，type为local时使用
+	Endpoint  string `json:"endpoint"`   //S3兼容服务地址，如MinIO的 http://localhost:9000
+	Region    string `json:"region"`     //区域，缺省为us-east-1
+	Bucket    string `json:"bucket"`     //存储桶，不存在时自动创建
+	AccessKey string `json:"access_key"` //访问密钥ID
+	SecretKey string `json:"secret_key"` //访问密钥
+}
+
+// 系统配置
+type Config struct {
+	RPCURL        string                `json:"rpc_url"`          //节点HTTP RPC地址，未配置chains时使用
+	WSURL         string                `json:"ws_url"`           //节点WebSocket RPC地址，未配置chains时使用
+	Chains        []Chain               `json:"chains,omitempty"` //同时连接的多条链，第一条为默认链
+	PublicURL     string                `json:"public_url"`       //后端对外访问地址，用于生成元数据中的图片链接，为空时取请求的Host
+	Confirmations uint64                `json:"confirmations"`    //交易确认所需区块数
+	Similarity    float64               `json:"similarity"`       //感知哈希相似度阈值（0~1），达到阈值的上传进入人工审核
+	Storage       Storage               `json:"storage"`          //内容存储后端
+	Upload        map[string]UploadType `json:"upload"`           //按文件类别（image/audio/video/pdf/text）配置的上传类型白名单和大小上限，mime为空表示禁止该类别
+	UploadExpiry  uint64                `json:"upload_expiry"`    //分片上传会话的过期时间（小时），超过该时间没有新分片的会话及临时文件会被清理，0表示不清理
+	Contracts     map[string]Contracts  `json:"contracts"`        //合约地址登记，键为chainId
+}
+
+// 全局配置，初始值即默认配置
+var Conf = Config{
+	RPCURL:        "http://localhost:8545",
+	WSURL:         "ws://localhost:8545",
+	Confirmations: 6,
+	Similarity:    0.9,
+	Storage:       Storage{Type: STORAGE_LOCAL, Dir: "static/contents", Region: "us-east-1"},
+	Upload: map[string]UploadType{
+		UPLOAD_IMAGE: {MIME: []string{"image/jpeg", "image/png", "image/gif"}, MaxSize: 20 << 20, MaxPixels: DEFAULT_MAX_PIXELS},
+		UPLOAD_AUDIO: {MIME: []string{"audio/mpeg", "audio/wave", "application/ogg"}, MaxSize: 200 << 20},
+		UPLOAD_VIDEO: {MIME: []string{"video/mp4", "video/webm"}, MaxSize: 4 << 30},
+		UPLOAD_PDF:   {MIME: []string{"application/pdf"}, MaxSize: 50 << 20},
+		UPLOAD_TEXT:  {MIME: []string{"text/plain"}, MaxSize: 5 << 20},
+	},
+	UploadExpiry: 24,
+	Contracts:    map[string]Contracts{},
+}
+
+// init自动加载配置文件，文件中未出现的字段保留默认值
+func init() {
+	data, err := os.ReadFile(CONFIG_FILE)
+	if err != nil && !os.IsNotExist(err) {
+		log.Panic("Failed to read config file ", err)
+	}
+	if err == nil {
+		if err = json.Unmarshal(data, &Conf); err != nil {
+			log.Panic("Failed to parse config file ", err)
+		}
+	}
+	if Conf.Contracts == nil {
+		Conf.Contracts = map[string]Contracts{}
+	}
+	//合并deploy子命令登记的合约地址
+	deployed, err := loadContracts()
+	if err != nil {
+		log.Panic("Failed to load contracts file ", err)
+	}
+	for chainID, c := range deployed {
+		Conf.Contracts[chainID] = c
+	}
+}
+
+// 读取合约地址登记文件，文件不存在时返回空登记
+func loadContracts() (map[string]Contracts, error) {
+	contracts := map[string]Contracts{}
+	data, err := os.ReadFile(CONTRACTS_FILE)
+	if os.IsNotExist(err) {
+		return contracts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// 按MIME类型查找所属的上传类别及其限制，不在白名单中时ok为false
+func UploadTypeOf(mimeType string) (kind string, t UploadType, ok bool) {
+	for kind, t := range Conf.Upload {
+		for _, m := range t.MIME {
+			if m == mimeType {
+				return kind, t, true
+			}
+		}
+	}
+	return "", UploadType{}, false
+}
+
+// 图片解码前允许的像素数上限，文件头声明的宽高超出时拒绝解码
+func ImageMaxPixels() int64 {
+	if n := Conf.Upload[UPLOAD_IMAGE].MaxPixels; n > 0 {
+		return n
+	}
+	return DEFAULT_MAX_PIXELS
+}
+
+// 需要连接的链，未配置chains时使用rpc_url和ws_url作为唯一的一条链
+func ChainConfs() []Chain {
+	if len(Conf.Chains) > 0 {
+		return Conf.Chains
+	}
+	return []Chain{{Name: "default", RPCURL: Conf.RPCURL, WSURL: Conf.WSURL}}
+}
+
+// 登记某条链的合约地址并写入合约登记文件，config.json保持不变
+func SaveContracts(chainID string, c Contracts) error {
+	Conf.Contracts[chainID] = c
+	contracts, err := loadContracts()
+	if err != nil {
+		return err
+	}
+	contracts[chainID] = c
+	data, err := json.MarshalIndent(contracts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(CONTRACTS_FILE, append(data, '\n'), 0644)
+}
@@ -3,7 +3,10 @@ package dbs
 import (
 	"copyright/utils"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math/big"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -17,29 +20,56 @@ type User struct {
 
 type Content struct {
 	Title       string `json:"title"`        //原图片名称
-	ContentPath string `json:"content"`      //图片保存路径
+	ContentPath string `json:"content"`      //图片保存路径（原件，仅份额持有人可访问）
+	Thumbnail   string `json:"thumbnail"`    //缩略图路径，公开访问
+	Preview     string `json:"preview"`      //带token ID水印的预览图路径，公开访问
 	ContentHash string `json:"content_hash"` //图片hash
-	Address     string `json:"address"`      //图片上传用户地址
+	CID         string `json:"cid"`          //IPFS CIDv1，可脱离本服务按内容寻址
+	PHash       string `json:"phash"`        //感知哈希（dHash），用于近似重复检测
+	MimeType    string `json:"mime_type"`    //按文件内容识别的MIME类型
+	FileExt     string `json:"file_ext"`     //保存文件的扩展名
+	Address     string `json:"address"`      //图片当前归属地址，整体转让后随之变更
 	TokenID     string `json:"token_id"`     //图片tokenid
+	ChainID     string `json:"chain_id"`     //铸造所在链ID
+	Creator     string `json:"creator"`      //图片上传用户（创作者）地址
+	RoyaltyBps  int64  `json:"royalty_bps"`  //二次销售版税（万分比），参照ERC-2981
+	CreatedAt   string `json:"created_at"`   //上传时间
 }
 
 type Auction struct {
-	ContentPath string `json:"content"`  //图片路径
-	Address     string `json:"address"`  //图片归属地址
-	UserName    string `json:"username"` //图片归属账号
-	TokenID     string `json:"token_id"` //图片tokenid
-	Weight      int    `json:"weight"`   //拍卖百分比
-	Price       int    `json:"price"`    //百分比单价
+	ContentPath  string `json:"content"`       //图片路径，有预览图时为带水印的预览图
+	Thumbnail    string `json:"thumbnail"`     //缩略图路径
+	Address      string `json:"address"`       //图片归属地址
+	UserName     string `json:"username"`      //图片归属账号
+	TokenID      string `json:"token_id"`      //图片tokenid
+	ChainID      string `json:"chain_id"`      //token所在链ID，按该链的PXC结算
+	MimeType     string `json:"mime_type"`     //内容MIME类型
+	Weight       int    `json:"weight"`        //拍卖百分比
+	Price        string `json:"price"`         //百分比单价，数据库中为PXC最小单位，接口中为十进制PXC金额
+	ListedWeight int    `json:"listed_weight"` //挂牌时的份额，即签名中的weight
+	Nonce        string `json:"nonce"`         //挂牌签名nonce
+	Signature    string `json:"signature"`     //卖家EIP-712挂牌签名
 }
 
 type AuctionHis struct {
-	Buyer     string `json:"buyer"`      //拍卖者
-	Address   string `json:"address"`    //图片归属账户
-	TokenID   string `json:"token_id"`   //图片tokenid
-	Weight    int64  `json:"weight"`     //拍卖百分比
-	Price     int64  `json:"price"`      //百分比单价
-	CreatedAt string `json:"created_at"` //创建时间
-	Content   string `json:"content"`    //内容路径
+	ID            int64  `json:"id"`              //主键ID
+	Buyer         string `json:"buyer"`           //拍卖者
+	Address       string `json:"address"`         //图片归属账户
+	TokenID       string `json:"token_id"`        //图片tokenid
+	Weight        int64  `json:"weight"`          //拍卖百分比
+	Price         string `json:"price"`           //百分比单价，数据库中为PXC最小单位，接口中为十进制PXC金额
+	CreatedAt     string `json:"created_at"`      //创建时间
+	Content       string `json:"content"`         //内容路径
+	PayTxHash     string `json:"pay_tx_hash"`     //PXC付款交易哈希（仅旧版分步交割的记录，结算合约交割不再使用）
+	TxHash        string `json:"tx_hash"`         //交割交易哈希（结算合约交易）
+	Creator       string `json:"creator"`         //版税收款人（创作者）地址
+	Royalty       string `json:"royalty"`         //版税金额
+	RoyaltyTxHash string `json:"royalty_tx_hash"` //版税付款交易哈希（仅旧版分步交割的记录）
+	Nonce         string `json:"nonce"`           //订单签名nonce
+	Signature     string `json:"signature"`       //买家EIP-712订单签名
+	BlockNumber   uint64 `json:"block_number"`    //交易所在区块
+	BlockHash     string `json:"block_hash"`      //交易所在区块哈希
+	Status        string `json:"status"`          //确认状态 pending/confirmed/failed
 }
 
 type EquityRegistration struct {
@@ -48,6 +78,9 @@ type EquityRegistration struct {
 	Weight      int64  `json:"weight"`     //拍卖百分比
 	CreatedAt   string `json:"created_at"` //创建时间
 	ContentPath string `json:"content"`    //图片保存路径
+	Thumbnail   string `json:"thumbnail"`  //缩略图路径
+	Preview     string `json:"preview"`    //预览图路径
+	MimeType    string `json:"mime_type"`  //内容MIME类型
 }
 
 // 数据库连接的全局变量
@@ -121,6 +154,27 @@ func (u *User) QueryByUsername(username string) (bool, error) {
 	return false, err
 }
 
+// 根据地址查询用户信息
+func (u *User) QueryByAddress(address string) (bool, error) {
+	rows, err := DBConn.Query("select email, username, address from t_user where address=?", address)
+	if err != nil {
+		fmt.Println("failed to select t_user by address", err)
+		return false, err
+	}
+	defer rows.Close()
+
+	// 有结果集
+	if rows.Next() {
+		err = rows.Scan(&u.Email, &u.UserName, &u.Address)
+		if err != nil {
+			fmt.Println("failed to scan select t_user by address", err)
+			return false, err
+		}
+		return true, nil
+	}
+	return false, rows.Err()
+}
+
 func (u *User) ListUsers(pageNum, pageSize int) (*utils.PageResult[User], error) {
 	// 参数校验
 	if pageNum <= 0 {
@@ -199,8 +253,12 @@ func (u *User) DeleteByAddress(address string) error {
 
 func (c *Content) AddContent() error {
 	fmt.Printf("%+v\n", c)
-	_, err := DBConn.Exec("insert into t_content(title,content,content_hash,address,token_id) values(?,?,?,?,?)",
-		c.Title, c.ContentPath, c.ContentHash, c.Address, c.TokenID)
+	// 上传用户即创作者
+	if c.Creator == "" {
+		c.Creator = c.Address
+	}
+	_, err := DBConn.Exec("insert into t_content(title,content,thumbnail,preview,content_hash,cid,phash,mime_type,file_ext,address,creator,royalty_bps,token_id,chain_id) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+		c.Title, c.ContentPath, c.Thumbnail, c.Preview, c.ContentHash, c.CID, c.PHash, c.MimeType, c.FileExt, c.Address, c.Creator, c.RoyaltyBps, c.TokenID, c.ChainID)
 	if err != nil {
 		fmt.Println("failed to insert t_content ", err)
 		return err
@@ -211,8 +269,8 @@ func (c *Content) AddContent() error {
 
 // QueryByTokenID方法用于根据token_id查询商品信息
 func (c *Content) QueryByTokenID(tokenID string) error {
-	// 执行查询，只查询title、content、content_hash字段
-	rows, err := DBConn.Query("select title, content, content_hash from t_content where token_id = ? limit 1", tokenID)
+	// 执行查询，creator为空的历史数据以上传地址作为创作者
+	rows, err := DBConn.Query("select title, content, thumbnail, preview, content_hash, cid, phash, mime_type, file_ext, address, if(creator = '', address, creator), royalty_bps, token_id, chain_id, created_at from t_content where token_id = ? limit 1", tokenID)
 	if err != nil {
 		fmt.Println("failed to query t_content by token_id", err)
 		return err
@@ -221,7 +279,7 @@ func (c *Content) QueryByTokenID(tokenID string) error {
 
 	// 处理查询结果
 	if rows.Next() {
-		err = rows.Scan(&c.Title, &c.ContentPath, &c.ContentHash)
+		err = rows.Scan(&c.Title, &c.ContentPath, &c.Thumbnail, &c.Preview, &c.ContentHash, &c.CID, &c.PHash, &c.MimeType, &c.FileExt, &c.Address, &c.Creator, &c.RoyaltyBps, &c.TokenID, &c.ChainID, &c.CreatedAt)
 		if err != nil {
 			fmt.Println("failed to scan t_content", err)
 			return err
@@ -237,10 +295,101 @@ func (c *Content) QueryByTokenID(tokenID string) error {
 	return nil
 }
 
+// QueryByContentHash方法用于根据内容哈希查询最早登记的内容，found为false表示该内容尚未登记
+func (c *Content) QueryByContentHash(contentHash string) (bool, error) {
+	err := DBConn.QueryRow("select title, content_hash, address, if(creator = '', address, creator), token_id, chain_id from t_content where content_hash = ? order by id limit 1", contentHash).
+		Scan(&c.Title, &c.ContentHash, &c.Address, &c.Creator, &c.TokenID, &c.ChainID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query t_content by content_hash", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// QueryByContentPath方法用于按原件路径查询内容，found为false表示不是已登记内容的原件
+func (c *Content) QueryByContentPath(contentPath string) (bool, error) {
+	err := DBConn.QueryRow("select title, content, token_id, chain_id from t_content where content = ? limit 1", contentPath).
+		Scan(&c.Title, &c.ContentPath, &c.TokenID, &c.ChainID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query t_content by content", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdatePreviews方法用于更新缩略图和预览图路径
+func (c *Content) UpdatePreviews() error {
+	_, err := DBConn.Exec("update t_content set thumbnail = ?, preview = ? where token_id = ?", c.Thumbnail, c.Preview, c.TokenID)
+	if err != nil {
+		fmt.Println("failed to update t_content previews", err)
+		return err
+	}
+	return nil
+}
+
+// 查询某条链上尚未生成预览图的图片内容，tokenID为空时查询全部；未记录MIME类型的历史数据均为图片
+func QueryMissingPreviews(chainID, tokenID string) ([]Content, error) {
+	sqlQuery := "select title, content, token_id, chain_id from t_content where chain_id = ? and preview = '' and (mime_type = '' or mime_type like 'image/%')"
+	args := []interface{}{chainID}
+	if tokenID != "" {
+		sqlQuery += " and token_id = ?"
+		args = append(args, tokenID)
+	}
+	rows, err := DBConn.Query(sqlQuery, args...)
+	if err != nil {
+		fmt.Println("failed to query t_content without preview", err)
+		return nil, err
+	}
+	defer rows.Close()
+	contents := []Content{}
+	for rows.Next() {
+		var c Content
+		if err = rows.Scan(&c.Title, &c.ContentPath, &c.TokenID, &c.ChainID); err != nil {
+			fmt.Println("failed to scan t_content", err)
+			return nil, err
+		}
+		contents = append(contents, c)
+	}
+	return contents, rows.Err()
+}
+
+// 查询所有已计算感知哈希的内容，用于相似度检索
+func QueryPHashes() ([]Content, error) {
+	rows, err := DBConn.Query("select title, content, content_hash, phash, mime_type, file_ext, address, if(creator = '', address, creator), token_id, chain_id from t_content where phash <> ''")
+	if err != nil {
+		fmt.Println("failed to query t_content phash", err)
+		return nil, err
+	}
+	defer rows.Close()
+	contents := []Content{}
+	for rows.Next() {
+		var c Content
+		err = rows.Scan(&c.Title, &c.ContentPath, &c.ContentHash, &c.PHash, &c.MimeType, &c.FileExt, &c.Address, &c.Creator, &c.TokenID, &c.ChainID)
+		if err != nil {
+			fmt.Println("failed to scan t_content phash", err)
+			return nil, err
+		}
+		contents = append(contents, c)
+	}
+	return contents, rows.Err()
+}
+
+// RoyaltyInfo方法参照ERC-2981的royaltyInfo，按成交金额计算应付给创作者的版税
+func (c *Content) RoyaltyInfo(salePrice *big.Int) (string, *big.Int) {
+	amount := new(big.Int).Mul(salePrice, big.NewInt(c.RoyaltyBps))
+	return c.Creator, amount.Quo(amount, big.NewInt(10000))
+}
+
 func QueryContents(address string) ([]Content, error) {
 	s := []Content{}
 	// 1.查询
-	rows, err := DBConn.Query("select title,content,content_hash,token_id from t_content where address =?", address)
+	rows, err := DBConn.Query("select title,content,content_hash,token_id,chain_id from t_content where address =?", address)
 	if err != nil {
 		fmt.Println("failed to Query t_content ", err)
 		return s, err
@@ -249,7 +398,7 @@ func QueryContents(address string) ([]Content, error) {
 	// 2.处理结果集
 	for rows.Next() {
 		var c Content
-		err = rows.Scan(&c.Title, &c.ContentPath, &c.ContentHash, &c.TokenID)
+		err = rows.Scan(&c.Title, &c.ContentPath, &c.ContentHash, &c.TokenID, &c.ChainID)
 		if err != nil {
 			fmt.Println("failed to scan select t_content ", err)
 			return s, err
@@ -259,6 +408,34 @@ func QueryContents(address string) ([]Content, error) {
 	return s, nil
 }
 
+// TransferTo方法用于整体转让内容：在同一事务中更新t_content归属地址，并在股权登记中从原持有人转出weight份额给新持有人
+func (c *Content) TransferTo(to string, weight int64) error {
+	tx, err := DBConn.Begin()
+	if err != nil {
+		fmt.Println("failed to begin transaction", err)
+		return err
+	}
+	_, err = tx.Exec("update t_content set address = ? where token_id = ?", to, c.TokenID)
+	if err != nil {
+		fmt.Println("failed to update t_content address", err)
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.Exec("insert into t_equity_registration(address, token_id, weight) values(?,?,?),(?,?,?)",
+		c.Address, c.TokenID, -weight, to, c.TokenID, weight)
+	if err != nil {
+		fmt.Println("failed to insert t_equity_registration", err)
+		tx.Rollback()
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		fmt.Println("failed to commit transaction", err)
+		return err
+	}
+	c.Address = to
+	return nil
+}
+
 // Add方法用于向数据库中插入新的股权注册记录
 func (er *EquityRegistration) AddEquityRegistration() error {
 	_, err := DBConn.Exec("insert into t_equity_registration(address, token_id, weight) values(?,?,?)",
@@ -276,11 +453,14 @@ func (er *EquityRegistration) QueryEquityByAddress(address string) ([]EquityRegi
 	sqlQuery := `SELECT 
 		er.token_id, 
 		SUM(er.weight) as total_weight, 
-		tc.content as content_path 
+		tc.content as content_path, 
+		tc.thumbnail, 
+		tc.preview, 
+		tc.mime_type 
 	FROM t_equity_registration er 
 	LEFT JOIN t_content tc ON er.token_id = tc.token_id 
 	WHERE er.address = ? 
-	GROUP BY er.token_id, tc.content`
+	GROUP BY er.token_id, tc.content, tc.thumbnail, tc.preview, tc.mime_type`
 
 	rows, err := DBConn.Query(sqlQuery, address)
 	if err != nil {
@@ -294,9 +474,9 @@ func (er *EquityRegistration) QueryEquityByAddress(address string) ([]EquityRegi
 	for rows.Next() {
 		var tokenID string
 		var totalWeight int64
-		var contentPath sql.NullString
+		var contentPath, thumbnail, preview, mimeType sql.NullString
 
-		err := rows.Scan(&tokenID, &totalWeight, &contentPath)
+		err := rows.Scan(&tokenID, &totalWeight, &contentPath, &thumbnail, &preview, &mimeType)
 		if err != nil {
 			fmt.Println("failed to scan equity data", err)
 			return nil, err
@@ -315,6 +495,9 @@ func (er *EquityRegistration) QueryEquityByAddress(address string) ([]EquityRegi
 		} else {
 			equity.ContentPath = ""
 		}
+		equity.Thumbnail = thumbnail.String
+		equity.Preview = preview.String
+		equity.MimeType = mimeType.String
 
 		result = append(result, equity)
 	}
@@ -328,10 +511,54 @@ func (er *EquityRegistration) QueryEquityByAddress(address string) ([]EquityRegi
 	return result, nil
 }
 
+// QueryEquityWeight方法用于查询某地址在原始token下登记的份额
+func QueryEquityWeight(address, tokenID string) (int64, error) {
+	var weight sql.NullInt64
+	err := DBConn.QueryRow("select sum(weight) from t_equity_registration where address = ? and token_id = ?", address, tokenID).Scan(&weight)
+	if err != nil {
+		fmt.Println("failed to query equity weight", err)
+		return 0, err
+	}
+	return weight.Int64, nil
+}
+
+// QueryEquityHolders方法用于按token和持有人汇总某条链上的股权份额，tokenID为空时查询该链全部token
+func QueryEquityHolders(chainID, tokenID string) ([]EquityRegistration, error) {
+	sqlQuery := "select er.address, er.token_id, sum(er.weight) from t_equity_registration er join t_content tc on er.token_id = tc.token_id where tc.chain_id = ?"
+	args := []interface{}{chainID}
+	if tokenID != "" {
+		sqlQuery += " and er.token_id = ?"
+		args = append(args, tokenID)
+	}
+	sqlQuery += " group by er.token_id, er.address order by er.token_id"
+
+	rows, err := DBConn.Query(sqlQuery, args...)
+	if err != nil {
+		fmt.Println("failed to query equity holders", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []EquityRegistration{}
+	for rows.Next() {
+		var er EquityRegistration
+		if err = rows.Scan(&er.Address, &er.TokenID, &er.Weight); err != nil {
+			fmt.Println("failed to scan equity holders", err)
+			return nil, err
+		}
+		result = append(result, er)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration", err)
+		return nil, err
+	}
+	return result, nil
+}
+
 func (a Auction) Add() error {
 	fmt.Println(a)
-	_, err := DBConn.Exec("insert into t_auction(token_id,weight,price,address) values(?,?,?,?)",
-		a.TokenID, a.Weight, a.Price, a.Address)
+	_, err := DBConn.Exec("insert into t_auction(token_id,weight,listed_weight,price,address,nonce,signature) values(?,?,?,?,?,?,?)",
+		a.TokenID, a.Weight, a.Weight, a.Price, a.Address, a.Nonce, a.Signature)
 	if err != nil {
 		fmt.Println("failed to insert t_auction ", err)
 		return err
@@ -339,6 +566,20 @@ func (a Auction) Add() error {
 	return err
 }
 
+// QueryListing方法用于根据卖家地址和token_id查询挂牌信息及签名
+func (a *Auction) QueryListing() (bool, error) {
+	err := DBConn.QueryRow("select weight, listed_weight, price, nonce, signature from t_auction where address = ? and token_id = ? limit 1",
+		a.Address, a.TokenID).Scan(&a.Weight, &a.ListedWeight, &a.Price, &a.Nonce, &a.Signature)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query t_auction listing", err)
+		return false, err
+	}
+	return true, nil
+}
+
 // Update方法用于执行份额扣减功能
 func (a Auction) UpdateWeight(deductWeight int64) error {
 	_, err := DBConn.Exec("update t_auction set weight = weight - ? where token_id = ? and address = ?",
@@ -354,7 +595,7 @@ func (a Auction) UpdateWeight(deductWeight int64) error {
 func (a Auction) QueryMyAuctions() ([]Auction, error) {
 	auctions := []Auction{}
 	// 执行查询，修正表名为t_auction，按created_at降序排序
-	rows, err := DBConn.Query("select distinct a.content, b.address, b.price, b.weight, b.token_id from t_auction b, t_content a where b.address = ? and b.token_id = a.token_id and b.weight>0 order by b.created_at desc", a.Address)
+	rows, err := DBConn.Query("select distinct if(a.preview = '', a.content, a.preview), a.thumbnail, a.mime_type, b.address, b.price, b.weight, b.token_id, a.chain_id, b.listed_weight, b.nonce, b.signature from t_auction b, t_content a where b.address = ? and b.token_id = a.token_id and b.weight>0 order by b.created_at desc", a.Address)
 	if err != nil {
 		fmt.Println("failed to query t_auction by address", err)
 		return auctions, err
@@ -364,7 +605,7 @@ func (a Auction) QueryMyAuctions() ([]Auction, error) {
 	var auction Auction
 	// 处理结果集
 	for rows.Next() {
-		err = rows.Scan(&auction.ContentPath, &auction.Address, &auction.Price, &auction.Weight, &auction.TokenID)
+		err = rows.Scan(&auction.ContentPath, &auction.Thumbnail, &auction.MimeType, &auction.Address, &auction.Price, &auction.Weight, &auction.TokenID, &auction.ChainID, &auction.ListedWeight, &auction.Nonce, &auction.Signature)
 		if err != nil {
 			fmt.Println("failed to scan t_auction", err)
 			return auctions, err
@@ -404,7 +645,7 @@ func (a Auction) CountByAddressAndTokenID() (int64, error) {
 func QueryAuctions(address string) ([]Auction, error) {
 	s := []Auction{}
 	// 1.查询
-	rows, err := DBConn.Query("select a.content,b.address,c.username,b.price,b.weight,a.token_id from t_content a,t_auction b,t_user c where a.token_id=b.token_id and b.address = c.address  and b.address <> ?  and b.weight > 0", address)
+	rows, err := DBConn.Query("select if(a.preview = '', a.content, a.preview),a.thumbnail,a.mime_type,b.address,c.username,b.price,b.weight,a.token_id,a.chain_id,b.listed_weight,b.nonce,b.signature from t_content a,t_auction b,t_user c where a.token_id=b.token_id and b.address = c.address  and b.address <> ?  and b.weight > 0", address)
 	if err != nil {
 		fmt.Println("failed to Query t_auction ", err)
 		return s, err
@@ -414,7 +655,7 @@ func QueryAuctions(address string) ([]Auction, error) {
 	// 2.处理结果集
 	//a.content,a.address,b.price,b.weight,a.token_id
 	for rows.Next() {
-		err = rows.Scan(&a.ContentPath, &a.Address, &a.UserName, &a.Price, &a.Weight, &a.TokenID)
+		err = rows.Scan(&a.ContentPath, &a.Thumbnail, &a.MimeType, &a.Address, &a.UserName, &a.Price, &a.Weight, &a.TokenID, &a.ChainID, &a.ListedWeight, &a.Nonce, &a.Signature)
 		if err != nil {
 			fmt.Println("failed to scan select t_aution & t_content ", err)
 			return s, err
@@ -424,16 +665,175 @@ func QueryAuctions(address string) ([]Auction, error) {
 	return s, nil
 }
 
-func (ah AuctionHis) Add() error {
-	_, err := DBConn.Exec("insert into t_auction_his(buyer,address,token_id,weight,price) values(?,?,?,?,?)",
-		ah.Buyer, ah.Address, ah.TokenID, ah.Weight, ah.Price)
+// 挂牌剩余份额不足，通常是并发出价已将份额扣减
+var ErrListingInsufficient = errors.New("listing has insufficient weight")
+
+// Add方法用于插入成交记录，并在同一事务中扣减卖家挂牌份额、将股权登记从卖家预先转给买家；
+// 交割交易失败时由Fail撤销
+func (ah *AuctionHis) Add() error {
+	tx, err := DBConn.Begin()
+	if err != nil {
+		fmt.Println("failed to begin transaction", err)
+		return err
+	}
+	defer tx.Rollback()
+	//1. 扣减挂牌份额，剩余份额不足时不扣减
+	result, err := tx.Exec("update t_auction set weight = weight - ? where token_id = ? and address = ? and weight >= ?",
+		ah.Weight, ah.TokenID, ah.Address, ah.Weight)
+	if err != nil {
+		fmt.Println("failed to update t_auction weight", err)
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return err
+	}
+	if n == 0 {
+		return ErrListingInsufficient
+	}
+	//2. 成交记录
+	result, err = tx.Exec("insert into t_auction_his(buyer,address,token_id,weight,price,nonce,signature) values(?,?,?,?,?,?,?)",
+		ah.Buyer, ah.Address, ah.TokenID, ah.Weight, ah.Price, ah.Nonce, ah.Signature)
 	if err != nil {
 		fmt.Println("failed to insert t_auction_his ", err)
 		return err
 	}
+	ah.ID, err = result.LastInsertId()
+	if err != nil {
+		fmt.Println("failed to get t_auction_his id ", err)
+		return err
+	}
+	//3. 卖家扣减、买家增加股权登记
+	_, err = tx.Exec("insert into t_equity_registration(address, token_id, weight) values(?,?,?),(?,?,?)",
+		ah.Address, ah.TokenID, -ah.Weight, ah.Buyer, ah.TokenID, ah.Weight)
+	if err != nil {
+		fmt.Println("failed to insert t_equity_registration ", err)
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		fmt.Println("failed to commit transaction", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateTx方法用于记录交割交易哈希及版税
+func (ah AuctionHis) UpdateTx() error {
+	if ah.Royalty == "" {
+		ah.Royalty = "0"
+	}
+	_, err := DBConn.Exec("update t_auction_his set tx_hash = ?, creator = ?, royalty = ? where id = ?",
+		ah.TxHash, ah.Creator, ah.Royalty, ah.ID)
+	if err != nil {
+		fmt.Println("failed to update t_auction_his tx_hash ", err)
+		return err
+	}
 	return nil
 }
 
+// UpdateStatus方法用于更新交易确认状态及所在区块
+func (ah AuctionHis) UpdateStatus() error {
+	_, err := DBConn.Exec("update t_auction_his set status = ?, block_number = ?, block_hash = ? where id = ?",
+		ah.Status, ah.BlockNumber, ah.BlockHash, ah.ID)
+	if err != nil {
+		fmt.Println("failed to update t_auction_his status ", err)
+		return err
+	}
+	return nil
+}
+
+// Fail方法用于将交易标记为失败，并在同一事务中撤销下单时预先扣减的挂牌份额和股权登记：
+// 挂牌份额加回卖家，股权登记追加一对反向记录；已标记失败的记录不会重复撤销
+func (ah AuctionHis) Fail() error {
+	_, err := ah.fail(0)
+	return err
+}
+
+// FailStale方法与Fail相同，但只撤销创建时间早于timeout之前的记录，返回是否已撤销
+func (ah AuctionHis) FailStale(timeout time.Duration) (bool, error) {
+	return ah.fail(timeout)
+}
+
+func (ah AuctionHis) fail(timeout time.Duration) (bool, error) {
+	tx, err := DBConn.Begin()
+	if err != nil {
+		fmt.Println("failed to begin transaction", err)
+		return false, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("update t_auction_his set status = ?, block_number = ?, block_hash = ? where id = ? and status <> ? and created_at <= now() - interval ? second",
+		STATUS_FAILED, ah.BlockNumber, ah.BlockHash, ah.ID, STATUS_FAILED, int64(timeout/time.Second))
+	if err != nil {
+		fmt.Println("failed to update t_auction_his status ", err)
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	// 挂牌已下架时无需加回
+	_, err = tx.Exec("update t_auction set weight = weight + ? where token_id = ? and address = ?",
+		ah.Weight, ah.TokenID, ah.Address)
+	if err != nil {
+		fmt.Println("failed to update t_auction weight", err)
+		return false, err
+	}
+	_, err = tx.Exec("insert into t_equity_registration(address, token_id, weight) values(?,?,?),(?,?,?)",
+		ah.Address, ah.TokenID, ah.Weight, ah.Buyer, ah.TokenID, -ah.Weight)
+	if err != nil {
+		fmt.Println("failed to insert t_equity_registration ", err)
+		return false, err
+	}
+	if err = tx.Commit(); err != nil {
+		fmt.Println("failed to commit transaction", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// QueryPendingTrades方法用于查询某条链上已提交交易但尚未确认的拍卖记录
+func QueryPendingTrades(chainID string) ([]AuctionHis, error) {
+	rows, err := DBConn.Query("select h.id, h.buyer, h.address, h.token_id, h.weight, h.tx_hash, h.block_number, h.block_hash from t_auction_his h join t_content tc on h.token_id = tc.token_id where tc.chain_id = ? and h.status = ? and h.tx_hash <> ''",
+		chainID, STATUS_PENDING)
+	if err != nil {
+		fmt.Println("failed to query pending t_auction_his ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []AuctionHis{}
+	for rows.Next() {
+		var a AuctionHis
+		if err = rows.Scan(&a.ID, &a.Buyer, &a.Address, &a.TokenID, &a.Weight, &a.TxHash, &a.BlockNumber, &a.BlockHash); err != nil {
+			fmt.Println("failed to scan pending t_auction_his ", err)
+			return nil, err
+		}
+		a.Status = STATUS_PENDING
+		result = append(result, a)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration ", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// CountPendingTrades方法用于统计token尚未确认的交割记录数量
+func CountPendingTrades(tokenID string) (int64, error) {
+	var count int64
+	err := DBConn.QueryRow("select count(*) from t_auction_his where token_id = ? and status = ?", tokenID, STATUS_PENDING).Scan(&count)
+	if err != nil {
+		fmt.Println("failed to count pending t_auction_his", err)
+		return 0, err
+	}
+	return count, nil
+}
+
 // QueryAuctionHis方法用于查询指定买家的拍卖历史记录，包含相关内容信息，按创建时间降序排序
 func (ah AuctionHis) QueryAuctionHis(pageNum, pageSize int) (*utils.PageResult[AuctionHis], error) {
 	// 参数校验
@@ -469,7 +869,14 @@ func (ah AuctionHis) QueryAuctionHis(pageNum, pageSize int) (*utils.PageResult[A
 		 his.weight, 
 		 his.price, 
 		 his.created_at, 
-		 tc.content 
+		 tc.content, 
+		 his.pay_tx_hash, 
+		 his.tx_hash, 
+		 his.creator, 
+		 his.royalty, 
+		 his.royalty_tx_hash, 
+		 his.block_number, 
+		 his.status 
 	 FROM t_auction_his his 
 	 LEFT JOIN ( 
 		 SELECT distinct token_id,content 
@@ -491,7 +898,7 @@ func (ah AuctionHis) QueryAuctionHis(pageNum, pageSize int) (*utils.PageResult[A
 	for rows.Next() {
 		var a AuctionHis
 		// 扫描所有查询结果列，包括新增的created_at和content字段
-		err := rows.Scan(&a.Buyer, &a.Address, &a.TokenID, &a.Weight, &a.Price, &a.CreatedAt, &a.Content)
+		err := rows.Scan(&a.Buyer, &a.Address, &a.TokenID, &a.Weight, &a.Price, &a.CreatedAt, &a.Content, &a.PayTxHash, &a.TxHash, &a.Creator, &a.Royalty, &a.RoyaltyTxHash, &a.BlockNumber, &a.Status)
 		if err != nil {
 			fmt.Println("failed to scan joined auction history data ", err)
 			return nil, err
@@ -515,3 +922,61 @@ func (ah AuctionHis) QueryAuctionHis(pageNum, pageSize int) (*utils.PageResult[A
 
 	return pageResult, nil
 }
+
+// QueryRoyaltyHis方法用于分页查询创作者收到的版税记录，不含交割失败的记录，按创建时间降序排序
+func QueryRoyaltyHis(creator string, pageNum, pageSize int) (*utils.PageResult[AuctionHis], error) {
+	// 参数校验
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	// 查询总记录数
+	var total int
+	countRow := DBConn.QueryRow("select count(*) from t_auction_his where creator = ? and royalty <> '0' and tx_hash <> '' and status <> ?", creator, STATUS_FAILED)
+	if err := countRow.Scan(&total); err != nil {
+		fmt.Println("failed to get total count of royalty history: ", err)
+		return nil, err
+	}
+
+	// 计算分页参数
+	offset := (pageNum - 1) * pageSize
+
+	sqlQuery := `select id, buyer, address, token_id, weight, price, created_at, creator, royalty, tx_hash, royalty_tx_hash, block_number, status 
+	from t_auction_his 
+	where creator = ? and royalty <> '0' and tx_hash <> '' and status <> ? 
+	order by created_at desc 
+	limit ? offset ?`
+	rows, err := DBConn.Query(sqlQuery, creator, STATUS_FAILED, pageSize, offset)
+	if err != nil {
+		fmt.Println("failed to query royalty history ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]AuctionHis, 0)
+	for rows.Next() {
+		var a AuctionHis
+		err := rows.Scan(&a.ID, &a.Buyer, &a.Address, &a.TokenID, &a.Weight, &a.Price, &a.CreatedAt, &a.Creator, &a.Royalty, &a.TxHash, &a.RoyaltyTxHash, &a.BlockNumber, &a.Status)
+		if err != nil {
+			fmt.Println("failed to scan royalty history ", err)
+			return nil, err
+		}
+		result = append(result, a)
+	}
+
+	// 检查遍历行时是否有错误
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration ", err)
+		return nil, err
+	}
+
+	return &utils.PageResult[AuctionHis]{
+		Rows:     result,
+		Total:    total,
+		PageSize: pageSize,
+		PageNum:  pageNum,
+	}, nil
+}
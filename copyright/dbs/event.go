@@ -0,0 +1,287 @@
+package dbs
+
+import (
+	"copyright/utils"
+	"database/sql"
+	"fmt"
+)
+
+// 链上合约事件，由索引器从PXA721和PXC20合约日志解析而来
+type ChainEvent struct {
+	ChainID     string `json:"chain_id"`     //链ID
+	Contract    string `json:"contract"`     //合约地址
+	Event       string `json:"event"`        //事件名称 Transfer/Approval/ApprovalForAll
+	From        string `json:"from"`         //Transfer的from，Approval/ApprovalForAll的owner
+	To          string `json:"to"`           //Transfer的to，Approval的spender/approved，ApprovalForAll的operator
+	Value       string `json:"value"`        //PXC金额或PXA tokenId（十进制字符串）
+	Approved    bool   `json:"approved"`     //ApprovalForAll的授权状态
+	BlockNumber uint64 `json:"block_number"` //区块高度
+	BlockHash   string `json:"block_hash"`   //区块哈希
+	TxHash      string `json:"tx_hash"`      //交易哈希
+	LogIndex    uint   `json:"log_index"`    //日志在区块中的序号
+	Status      string `json:"status"`       //确认状态 pending/confirmed
+}
+
+// 事件确认状态
+const (
+	STATUS_PENDING   = "pending"
+	STATUS_CONFIRMED = "confirmed"
+	STATUS_FAILED    = "failed"
+)
+
+// 事件查询条件
+type ChainEventQuery struct {
+	ChainID   string //链ID
+	Contract  string //合约地址
+	Address   string //参与地址，匹配from或to
+	Event     string //事件名称，为空表示全部
+	FromBlock uint64 //起始区块，0表示不限
+	ToBlock   uint64 //结束区块，0表示不限
+}
+
+// QueryChainEvents方法用于分页查询某地址参与的合约事件，按区块倒序排序
+func QueryChainEvents(q ChainEventQuery, pageNum, pageSize int) (*utils.PageResult[ChainEvent], error) {
+	// 参数校验
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	// 组装查询条件
+	where := "where chain_id = ? and contract = ? and (from_addr = ? or to_addr = ?)"
+	args := []interface{}{q.ChainID, q.Contract, q.Address, q.Address}
+	if q.Event != "" {
+		where += " and event = ?"
+		args = append(args, q.Event)
+	}
+	if q.FromBlock > 0 {
+		where += " and block_number >= ?"
+		args = append(args, q.FromBlock)
+	}
+	if q.ToBlock > 0 {
+		where += " and block_number <= ?"
+		args = append(args, q.ToBlock)
+	}
+
+	// 查询总记录数
+	var total int
+	countRow := DBConn.QueryRow("select count(*) from t_chain_event "+where, args...)
+	if err := countRow.Scan(&total); err != nil {
+		fmt.Println("failed to get total count of t_chain_event: ", err)
+		return nil, err
+	}
+
+	// 计算分页参数
+	offset := (pageNum - 1) * pageSize
+
+	sqlQuery := `select chain_id, contract, event, from_addr, to_addr, value, approved, block_number, block_hash, tx_hash, log_index, status 
+	from t_chain_event ` + where + ` 
+	order by block_number desc, log_index desc 
+	limit ? offset ?`
+	rows, err := DBConn.Query(sqlQuery, append(args, pageSize, offset)...)
+	if err != nil {
+		fmt.Println("failed to query t_chain_event ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]ChainEvent, 0)
+	for rows.Next() {
+		var e ChainEvent
+		err := rows.Scan(&e.ChainID, &e.Contract, &e.Event, &e.From, &e.To, &e.Value, &e.Approved, &e.BlockNumber, &e.BlockHash, &e.TxHash, &e.LogIndex, &e.Status)
+		if err != nil {
+			fmt.Println("failed to scan t_chain_event ", err)
+			return nil, err
+		}
+		result = append(result, e)
+	}
+
+	// 检查遍历行时是否有错误
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration ", err)
+		return nil, err
+	}
+
+	return &utils.PageResult[ChainEvent]{
+		Rows:     result,
+		Total:    total,
+		PageSize: pageSize,
+		PageNum:  pageNum,
+	}, nil
+}
+
+// QueryMintEvent方法用于查询某token的铸造事件（从零地址转出的Transfer），found为false表示尚未索引到
+func QueryMintEvent(chainID, contract, tokenID, zeroAddr string) (e ChainEvent, found bool, err error) {
+	err = DBConn.QueryRow(`select chain_id, contract, event, from_addr, to_addr, value, approved, block_number, block_hash, tx_hash, log_index, status 
+	from t_chain_event 
+	where chain_id = ? and contract = ? and event = 'Transfer' and from_addr = ? and value = ? and status <> ? 
+	order by block_number, log_index limit 1`, chainID, contract, zeroAddr, tokenID, STATUS_FAILED).
+		Scan(&e.ChainID, &e.Contract, &e.Event, &e.From, &e.To, &e.Value, &e.Approved, &e.BlockNumber, &e.BlockHash, &e.TxHash, &e.LogIndex, &e.Status)
+	if err == sql.ErrNoRows {
+		return e, false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query mint event", err)
+		return e, false, err
+	}
+	return e, true, nil
+}
+
+// QueryCheckpoint方法用于查询某条链上合约已索引到的区块高度，found为false表示尚未开始索引
+func QueryCheckpoint(chainID, contract string) (block uint64, found bool, err error) {
+	err = DBConn.QueryRow("select block_number from t_index_checkpoint where chain_id = ? and contract = ?", chainID, contract).Scan(&block)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query t_index_checkpoint", err)
+		return 0, false, err
+	}
+	return block, true, nil
+}
+
+// SaveChainEvents方法用于在同一事务中保存一批事件并推进检查点
+func SaveChainEvents(chainID, contract string, events []ChainEvent, block uint64) error {
+	tx, err := DBConn.Begin()
+	if err != nil {
+		fmt.Println("failed to begin transaction", err)
+		return err
+	}
+	// 重复扫描同一区块时依赖(tx_hash, log_index)唯一索引去重；
+	// 交易因链重组被打包进新区块时改写区块信息并恢复为未确认，避免随后清理旧区块时把该事件一并删除
+	for _, e := range events {
+		_, err = tx.Exec("insert into t_chain_event(chain_id, contract, event, from_addr, to_addr, value, approved, block_number, block_hash, tx_hash, log_index, status) values(?,?,?,?,?,?,?,?,?,?,?,?) "+
+			"on duplicate key update status = if(block_hash = values(block_hash), status, values(status)), block_number = values(block_number), block_hash = values(block_hash)",
+			chainID, e.Contract, e.Event, e.From, e.To, e.Value, e.Approved, e.BlockNumber, e.BlockHash, e.TxHash, e.LogIndex, e.Status)
+		if err != nil {
+			fmt.Println("failed to insert t_chain_event", err)
+			tx.Rollback()
+			return err
+		}
+	}
+	_, err = tx.Exec("insert into t_index_checkpoint(chain_id, contract, block_number) values(?,?,?) on duplicate key update block_number = values(block_number)",
+		chainID, contract, block)
+	if err != nil {
+		fmt.Println("failed to update t_index_checkpoint", err)
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// 未确认事件所在的区块
+type PendingBlock struct {
+	Number uint64
+	Hash   string
+}
+
+// QueryPendingBlocks方法用于查询某条链上合约未确认事件所在的区块，用于检查链重组
+func QueryPendingBlocks(chainID, contract string) ([]PendingBlock, error) {
+	rows, err := DBConn.Query("select distinct block_number, block_hash from t_chain_event where chain_id = ? and contract = ? and status = ?",
+		chainID, contract, STATUS_PENDING)
+	if err != nil {
+		fmt.Println("failed to query pending t_chain_event", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []PendingBlock{}
+	for rows.Next() {
+		var b PendingBlock
+		if err = rows.Scan(&b.Number, &b.Hash); err != nil {
+			fmt.Println("failed to scan pending t_chain_event", err)
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration", err)
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// DeleteEventsByBlockHash方法用于回滚某条链上已不在主链上的区块中的事件
+func DeleteEventsByBlockHash(chainID, blockHash string) error {
+	_, err := DBConn.Exec("delete from t_chain_event where chain_id = ? and block_hash = ? and status = ?", chainID, blockHash, STATUS_PENDING)
+	if err != nil {
+		fmt.Println("failed to delete t_chain_event by block_hash", err)
+		return err
+	}
+	return nil
+}
+
+// ConfirmEvents方法用于将达到确认深度的事件标记为已确认
+func ConfirmEvents(chainID, contract string, maxBlock uint64) error {
+	_, err := DBConn.Exec("update t_chain_event set status = ? where chain_id = ? and contract = ? and status = ? and block_number <= ?",
+		STATUS_CONFIRMED, chainID, contract, STATUS_PENDING, maxBlock)
+	if err != nil {
+		fmt.Println("failed to confirm t_chain_event", err)
+		return err
+	}
+	return nil
+}
+
+// QueryTransferTargets方法用于查询某条链上合约全部Transfer事件的接收地址和tokenId
+func QueryTransferTargets(chainID, contract string) ([]ChainEvent, error) {
+	rows, err := DBConn.Query("select distinct to_addr, value from t_chain_event where chain_id = ? and contract = ? and event = 'Transfer'", chainID, contract)
+	if err != nil {
+		fmt.Println("failed to query transfer targets", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []ChainEvent{}
+	for rows.Next() {
+		var e ChainEvent
+		if err = rows.Scan(&e.To, &e.Value); err != nil {
+			fmt.Println("failed to scan transfer targets", err)
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryOperators方法用于查询owner在某条链上合约ApprovalForAll事件中授权过的operator地址
+func QueryOperators(chainID, contract, owner string) ([]string, error) {
+	rows, err := DBConn.Query("select distinct to_addr from t_chain_event where chain_id = ? and contract = ? and event = 'ApprovalForAll' and from_addr = ?", chainID, contract, owner)
+	if err != nil {
+		fmt.Println("failed to query operators", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []string{}
+	for rows.Next() {
+		var operator string
+		if err = rows.Scan(&operator); err != nil {
+			fmt.Println("failed to scan operators", err)
+			return nil, err
+		}
+		result = append(result, operator)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// AssignDefaultChain方法用于将升级前未标记链ID的内容、事件和索引检查点归属到默认链
+func AssignDefaultChain(chainID string) error {
+	for _, table := range []string{"t_content", "t_chain_event", "t_index_checkpoint"} {
+		_, err := DBConn.Exec("update "+table+" set chain_id = ? where chain_id = ''", chainID)
+		if err != nil {
+			fmt.Println("failed to assign default chain for", table, err)
+			return err
+		}
+	}
+	return nil
+}
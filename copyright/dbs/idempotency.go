@@ -0,0 +1,77 @@
+package dbs
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// 幂等记录，保存同一Idempotency-Key的首次响应
+type Idempotency struct {
+	Key      string `json:"key"`       //Idempotency-Key请求头
+	Address  string `json:"address"`   //请求用户地址
+	Method   string `json:"method"`    //请求方法
+	Path     string `json:"path"`      //请求路径
+	BodyHash string `json:"body_hash"` //查询参数和请求体的哈希，同一个键只能用于相同的请求
+	Status   int    `json:"status"`    //HTTP状态码，0表示处理中
+	Response string `json:"response"`  //首次响应内容
+}
+
+// Reserve方法用于占用幂等键，返回false表示该键在保留期内已被使用
+func (i *Idempotency) Reserve(retention time.Duration) (bool, error) {
+	// 清理超过保留期的旧记录，过期的键可以被重新使用
+	_, err := DBConn.Exec("delete from t_idempotency where idem_key = ? and address = ? and method = ? and path = ? and created_at < DATE_SUB(NOW(), INTERVAL ? SECOND)",
+		i.Key, i.Address, i.Method, i.Path, int64(retention/time.Second))
+	if err != nil {
+		fmt.Println("failed to delete expired t_idempotency", err)
+		return false, err
+	}
+	// 依赖唯一索引保证并发请求中只有一个能占用成功
+	result, err := DBConn.Exec("insert ignore into t_idempotency(idem_key, address, method, path, body_hash) values(?,?,?,?,?)",
+		i.Key, i.Address, i.Method, i.Path, i.BodyHash)
+	if err != nil {
+		fmt.Println("failed to insert t_idempotency", err)
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Query方法用于查询已占用幂等键的请求哈希、状态和首次响应
+func (i *Idempotency) Query() error {
+	var response sql.NullString
+	err := DBConn.QueryRow("select body_hash, status, response from t_idempotency where idem_key = ? and address = ? and method = ? and path = ?",
+		i.Key, i.Address, i.Method, i.Path).Scan(&i.BodyHash, &i.Status, &response)
+	if err != nil {
+		fmt.Println("failed to query t_idempotency", err)
+		return err
+	}
+	i.Response = response.String
+	return nil
+}
+
+// Save方法用于保存首次请求的响应结果
+func (i *Idempotency) Save() error {
+	_, err := DBConn.Exec("update t_idempotency set status = ?, response = ? where idem_key = ? and address = ? and method = ? and path = ?",
+		i.Status, i.Response, i.Key, i.Address, i.Method, i.Path)
+	if err != nil {
+		fmt.Println("failed to update t_idempotency", err)
+		return err
+	}
+	return nil
+}
+
+// Release方法用于释放未产生响应或处理失败的幂等键，允许客户端重试
+func (i *Idempotency) Release() error {
+	_, err := DBConn.Exec("delete from t_idempotency where idem_key = ? and address = ? and method = ? and path = ? and status = 0",
+		i.Key, i.Address, i.Method, i.Path)
+	if err != nil {
+		fmt.Println("failed to delete t_idempotency", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package dbs
+
+import (
+	"copyright/utils"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// 审核状态
+const (
+	MODERATION_PENDING  = "pending"
+	MODERATION_APPROVED = "approved"
+	MODERATION_REJECTED = "rejected"
+)
+
+// 待审核的上传：与已登记内容感知哈希相似度达到阈值，审核通过后才登记和铸造
+type Moderation struct {
+	ID             int64   `json:"id"` //主键ID
+	Content                //待登记的内容
+	SimilarTokenID string  `json:"similar_token_id"` //最相似的已登记内容
+	Similarity     float64 `json:"similarity"`       //与该内容的相似度（0~1）
+	Status         string  `json:"status"`           //审核状态 pending/approved/rejected
+	Reviewer       string  `json:"reviewer"`         //审核人地址
+}
+
+// AddModeration方法用于将上传加入审核队列
+func (m *Moderation) AddModeration() error {
+	if m.Creator == "" {
+		m.Creator = m.Address
+	}
+	result, err := DBConn.Exec("insert into t_moderation(title,content,thumbnail,preview,content_hash,cid,phash,mime_type,file_ext,address,creator,royalty_bps,token_id,chain_id,similar_token_id,similarity,status) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+		m.Title, m.ContentPath, m.Thumbnail, m.Preview, m.ContentHash, m.CID, m.PHash, m.MimeType, m.FileExt, m.Address, m.Creator, m.RoyaltyBps, m.TokenID, m.ChainID, m.SimilarTokenID, m.Similarity, MODERATION_PENDING)
+	if err != nil {
+		fmt.Println("failed to insert t_moderation", err)
+		return err
+	}
+	m.ID, err = result.LastInsertId()
+	if err != nil {
+		fmt.Println("failed to get t_moderation id", err)
+		return err
+	}
+	m.Status = MODERATION_PENDING
+	return nil
+}
+
+// QueryByID方法用于查询审核记录
+func (m *Moderation) QueryByID(id int64) error {
+	err := DBConn.QueryRow("select id, title, content, thumbnail, preview, content_hash, cid, phash, mime_type, file_ext, address, creator, royalty_bps, token_id, chain_id, similar_token_id, similarity, status, reviewer, created_at from t_moderation where id = ?", id).
+		Scan(&m.ID, &m.Title, &m.ContentPath, &m.Thumbnail, &m.Preview, &m.ContentHash, &m.CID, &m.PHash, &m.MimeType, &m.FileExt, &m.Address, &m.Creator, &m.RoyaltyBps, &m.TokenID, &m.ChainID, &m.SimilarTokenID, &m.Similarity, &m.Status, &m.Reviewer, &m.CreatedAt)
+	if err != nil {
+		fmt.Println("failed to query t_moderation", err)
+		return err
+	}
+	return nil
+}
+
+// QueryPendingByContentHash方法用于查询内容哈希相同的待审核记录，found为false表示不存在
+func (m *Moderation) QueryPendingByContentHash(hash string) (found bool, err error) {
+	err = DBConn.QueryRow("select id, token_id, chain_id, address, similar_token_id from t_moderation where content_hash = ? and status = ? order by id limit 1", hash, MODERATION_PENDING).
+		Scan(&m.ID, &m.TokenID, &m.ChainID, &m.Address, &m.SimilarTokenID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query t_moderation by content hash", err)
+		return false, err
+	}
+	m.ContentHash = hash
+	m.Status = MODERATION_PENDING
+	return true, nil
+}
+
+// Review方法用于将待审核记录置为审核结果，已被其他审核人处理时返回错误
+func (m *Moderation) Review(status, reviewer string) error {
+	result, err := DBConn.Exec("update t_moderation set status = ?, reviewer = ? where id = ? and status = ?",
+		status, reviewer, m.ID, MODERATION_PENDING)
+	if err != nil {
+		fmt.Println("failed to update t_moderation", err)
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return err
+	}
+	if n != 1 {
+		return errors.New("moderation already reviewed")
+	}
+	m.Status = status
+	m.Reviewer = reviewer
+	return nil
+}
+
+// Reopen方法用于审核通过后铸造或登记失败时，将记录恢复为待审核，以便重新审核
+func (m *Moderation) Reopen() error {
+	_, err := DBConn.Exec("update t_moderation set status = ?, reviewer = '' where id = ? and status = ?",
+		MODERATION_PENDING, m.ID, MODERATION_APPROVED)
+	if err != nil {
+		fmt.Println("failed to update t_moderation", err)
+		return err
+	}
+	m.Status = MODERATION_PENDING
+	m.Reviewer = ""
+	return nil
+}
+
+// 分页查询某条链上指定状态的审核记录，status为空时查询全部
+func QueryModerations(chainID, status string, pageNum, pageSize int) (*utils.PageResult[Moderation], error) {
+	where := " where chain_id = ?"
+	args := []interface{}{chainID}
+	if status != "" {
+		where += " and status = ?"
+		args = append(args, status)
+	}
+	var total int
+	err := DBConn.QueryRow("select count(*) from t_moderation"+where, args...).Scan(&total)
+	if err != nil {
+		fmt.Println("failed to count t_moderation", err)
+		return nil, err
+	}
+	args = append(args, pageSize, (pageNum-1)*pageSize)
+	rows, err := DBConn.Query("select id, title, content, thumbnail, preview, content_hash, cid, phash, mime_type, file_ext, address, creator, royalty_bps, token_id, chain_id, similar_token_id, similarity, status, reviewer, created_at from t_moderation"+where+" order by id desc limit ? offset ?", args...)
+	if err != nil {
+		fmt.Println("failed to query t_moderation", err)
+		return nil, err
+	}
+	defer rows.Close()
+	list := []Moderation{}
+	for rows.Next() {
+		var m Moderation
+		err = rows.Scan(&m.ID, &m.Title, &m.ContentPath, &m.Thumbnail, &m.Preview, &m.ContentHash, &m.CID, &m.PHash, &m.MimeType, &m.FileExt, &m.Address, &m.Creator, &m.RoyaltyBps, &m.TokenID, &m.ChainID, &m.SimilarTokenID, &m.Similarity, &m.Status, &m.Reviewer, &m.CreatedAt)
+		if err != nil {
+			fmt.Println("failed to scan t_moderation", err)
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration ", err)
+		return nil, err
+	}
+	return &utils.PageResult[Moderation]{
+		Rows:     list,
+		Total:    total,
+		PageSize: pageSize,
+		PageNum:  pageNum,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package dbs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EIP-712签名类型
+const (
+	NONCE_LISTING = "listing"
+	NONCE_ORDER   = "order"
+)
+
+// 登记已使用的签名nonce，返回false表示该签名者在本链上已使用过同类型的nonce
+// nonce需为规范化的十进制字符串，依赖唯一索引保证并发请求中只有一个能登记成功
+func ConsumeNonce(chainID, signer, kind, nonce string) (bool, error) {
+	result, err := DBConn.Exec("insert ignore into t_signature_nonce(chain_id, signer, kind, nonce) values(?,?,?,?)",
+		chainID, strings.ToLower(signer), kind, nonce)
+	if err != nil {
+		fmt.Println("failed to insert t_signature_nonce", err)
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,93 @@
+package dbs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 连接MYSQL_TEST_DSN指定的测试库（需已按copyright.sql建表），未设置时跳过
+// 例如 MYSQL_TEST_DSN="root:1234@tcp(localhost:3306)/copyright_test?charset=utf8" go test ./dbs
+func useTestDB(t *testing.T) {
+	t.Helper()
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN not set")
+	}
+	db := InitDB(dsn, "mysql")
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	old := DBConn
+	DBConn = db
+	t.Cleanup(func() {
+		DBConn = old
+		db.Close()
+	})
+}
+
+func TestConsumeNonce(t *testing.T) {
+	useTestDB(t)
+	chainID := "31337"
+	signer := "0xAbC0000000000000000000000000000000000001"
+	nonce := fmt.Sprint(time.Now().UnixNano())
+	t.Cleanup(func() {
+		DBConn.Exec("delete from t_signature_nonce where nonce = ?", nonce)
+	})
+
+	consume := func(chainID, signer, kind string) bool {
+		t.Helper()
+		ok, err := ConsumeNonce(chainID, signer, kind, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+	if !consume(chainID, signer, NONCE_LISTING) {
+		t.Fatal("first use of nonce rejected")
+	}
+	// 同一签名者重复使用，地址大小写不同也视为同一签名者
+	if consume(chainID, signer, NONCE_LISTING) {
+		t.Error("reused listing nonce accepted")
+	}
+	if consume(chainID, strings.ToLower(signer), NONCE_LISTING) {
+		t.Error("reused listing nonce accepted for lowercase signer")
+	}
+	// 订单与挂牌、不同签名者、不同链的nonce相互独立
+	if !consume(chainID, signer, NONCE_ORDER) {
+		t.Error("order nonce rejected after listing used the same value")
+	}
+	if !consume(chainID, "0xabc0000000000000000000000000000000000002", NONCE_LISTING) {
+		t.Error("nonce of another signer rejected")
+	}
+	if !consume("1", signer, NONCE_LISTING) {
+		t.Error("nonce on another chain rejected")
+	}
+}
+
+func TestConsumeNonceConcurrent(t *testing.T) {
+	useTestDB(t)
+	nonce := fmt.Sprint(time.Now().UnixNano())
+	t.Cleanup(func() {
+		DBConn.Exec("delete from t_signature_nonce where nonce = ?", nonce)
+	})
+	// 并发使用同一个nonce只有一个成功
+	results := make(chan bool, 10)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			ok, err := ConsumeNonce("31337", "0xabc0000000000000000000000000000000000003", NONCE_ORDER, nonce)
+			results <- ok && err == nil
+		}()
+	}
+	accepted := 0
+	for i := 0; i < cap(results); i++ {
+		if <-results {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("%d concurrent uses of the same nonce accepted, want 1", accepted)
+	}
+}
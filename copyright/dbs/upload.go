@@ -0,0 +1,113 @@
+package dbs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 分片上传会话，支持断点续传
+type Upload struct {
+	UploadID  string `json:"upload_id"`  //上传ID
+	Address   string `json:"address"`    //上传用户地址
+	FileName  string `json:"file_name"`  //原始文件名
+	Size      int64  `json:"size"`       //文件总大小（字节）
+	Received  int64  `json:"received"`   //已接收的字节数，续传从该偏移开始
+	CreatedAt string `json:"created_at"` //创建时间
+}
+
+// AddUpload方法用于创建分片上传会话
+func (u *Upload) AddUpload() error {
+	_, err := DBConn.Exec("insert into t_upload(upload_id, address, file_name, size) values(?,?,?,?)",
+		u.UploadID, u.Address, u.FileName, u.Size)
+	if err != nil {
+		fmt.Println("failed to insert t_upload", err)
+		return err
+	}
+	return nil
+}
+
+// QueryUpload方法用于查询某用户的分片上传会话，found为false表示不存在
+func (u *Upload) QueryUpload(uploadID, address string) (bool, error) {
+	err := DBConn.QueryRow("select upload_id, address, file_name, size, received, created_at from t_upload where upload_id = ? and address = ?", uploadID, address).
+		Scan(&u.UploadID, &u.Address, &u.FileName, &u.Size, &u.Received, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to query t_upload", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateReceived方法用于在写入分片后更新已接收字节数，from为写入前的偏移
+func (u *Upload) UpdateReceived(from, received int64) error {
+	result, err := DBConn.Exec("update t_upload set received = ? where upload_id = ? and received = ?", received, u.UploadID, from)
+	if err != nil {
+		fmt.Println("failed to update t_upload", err)
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return err
+	}
+	if n != 1 {
+		return errors.New("upload offset changed concurrently")
+	}
+	u.Received = received
+	return nil
+}
+
+// DeleteUpload方法用于在上传完成或取消后删除会话
+func (u *Upload) DeleteUpload() error {
+	_, err := DBConn.Exec("delete from t_upload where upload_id = ?", u.UploadID)
+	if err != nil {
+		fmt.Println("failed to delete t_upload", err)
+		return err
+	}
+	return nil
+}
+
+// QueryExpiredUploads用于查询超过expiry没有更新的分片上传会话
+func QueryExpiredUploads(expiry time.Duration) ([]Upload, error) {
+	rows, err := DBConn.Query("select upload_id, address, file_name, size, received, created_at from t_upload where updated_at < DATE_SUB(NOW(), INTERVAL ? SECOND)",
+		int64(expiry/time.Second))
+	if err != nil {
+		fmt.Println("failed to query expired t_upload", err)
+		return nil, err
+	}
+	defer rows.Close()
+	uploads := []Upload{}
+	for rows.Next() {
+		var u Upload
+		if err = rows.Scan(&u.UploadID, &u.Address, &u.FileName, &u.Size, &u.Received, &u.CreatedAt); err != nil {
+			fmt.Println("failed to scan t_upload", err)
+			return nil, err
+		}
+		uploads = append(uploads, u)
+	}
+	if err = rows.Err(); err != nil {
+		fmt.Println("error during rows iteration", err)
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// DeleteExpired方法用于删除仍处于过期状态的会话，返回false表示会话已不存在或期间有更新
+func (u *Upload) DeleteExpired(expiry time.Duration) (bool, error) {
+	result, err := DBConn.Exec("delete from t_upload where upload_id = ? and updated_at < DATE_SUB(NOW(), INTERVAL ? SECOND)",
+		u.UploadID, int64(expiry/time.Second))
+	if err != nil {
+		fmt.Println("failed to delete t_upload", err)
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		fmt.Println("failed to get affected rows", err)
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"copyright/eths"
+	"flag"
+	"fmt"
+	"log"
+)
+
+// hardhat node内置的第一个测试账户私钥，私钥公开，仅在本地开发链上作为缺省部署账户
+const HARDHAT_DEPLOYER_KEY = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// hardhat本地开发链的链ID
+const HARDHAT_CHAIN_ID = "31337"
+
+// deploy子命令：go run . deploy [-chain 链ID或名称] [-key 私钥] [-fund 以太币数量]
+func deploy(args []string) {
+	fs := flag.NewFlagSet("deploy", flag.ExitOnError)
+	chain := fs.String("chain", "", "部署到的链（链ID或config.json中的链名称），缺省为默认链")
+	key := fs.String("key", "", "部署账户私钥（需有以太币余额），仅hardhat本地链（链ID 31337）可省略")
+	fund := fs.String("fund", "1000", "转给管理员的以太币数量(ETH)")
+	fs.Parse(args)
+
+	//1. 换算转账金额 ETH -> wei
+	ether := eths.Money{Decimals: 18, Symbol: "ETH"}
+	wei, err := ether.Parse(*fund)
+	if err != nil {
+		log.Fatal("invalid fund amount ", *fund)
+	}
+
+	//2. 部署合约并登记地址
+	ch, err := eths.GetChain(*chain)
+	if err != nil {
+		log.Fatal("unknown chain ", *chain)
+	}
+	// 公开的hardhat测试私钥只能用于本地开发链，其他链必须指定部署账户
+	if *key == "" {
+		if ch.ChainID() != HARDHAT_CHAIN_ID {
+			log.Fatal("-key is required when deploying to chain ", ch.ChainID())
+		}
+		*key = HARDHAT_DEPLOYER_KEY
+	}
+	d, err := ch.Deploy(*key, wei)
+	if err != nil {
+		log.Fatal("Failed to deploy contracts ", err)
+	}
+	fmt.Println("chain id:", d.ChainID)
+	fmt.Println("ERC20 (PXC20):", d.Contracts.PXC20)
+	fmt.Println("ERC721 (PXA721):", d.Contracts.PXA721)
+	fmt.Println("Settlement:", d.Contracts.Settlement)
+	fmt.Println("deploy block:", d.Contracts.DeployBlock)
+	if d.FundTx != "" {
+		fmt.Println("fund tx:", d.FundTx)
+	}
+}
@@ -2,6 +2,7 @@ package eths
 
 import (
 	"fmt"
+	"hash"
 
 	"copyright/hdwallet"
 
@@ -23,3 +24,8 @@ func NewAcc(pass string) (string, error) {
 func KeccakHash(data []byte) []byte {
 	return crypto.Keccak256(data)
 }
+
+// 流式计算Keccak256，用于大文件边写入边计算哈希
+func NewKeccak() hash.Hash {
+	return crypto.NewKeccakState()
+}
@@ -0,0 +1,190 @@
+package eths
+
+import (
+	"copyright/configs"
+	"copyright/dbs"
+	"copyright/hdkeystore"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC721合约默认地址，即hardhat本地链的部署地址，未在合约登记中配置时使用
+const DEFAULT_PXA_ADDR = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+
+// ERC20合约默认地址
+const DEFAULT_PXC_ADDR = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+
+// 结算合约默认地址
+const DEFAULT_SETTLE_ADDR = "0x9fE46736679d2D9a65F0992F2272dE9f3c7fa6e0"
+
+// 管理员默认用户名和keystore密码
+const (
+	DEFAULT_ADMIN      = "admin"
+	DEFAULT_ADMIN_PASS = "1234"
+)
+
+// 一条链的连接：节点客户端、合约实例和管理员签名账户
+type Chain struct {
+	ID   *big.Int //链ID
+	Name string   //链名称
+
+	cli   *ethclient.Client //Geth客户端连接句柄
+	wsURL string            //WebSocket RPC地址，事件订阅使用
+
+	pxaAddr     string //ERC721合约地址
+	pxcAddr     string //ERC20合约地址
+	settleAddr  string //结算合约地址
+	deployBlock uint64 //合约部署所在区块，索引器从该区块开始扫描
+
+	pxa    *Pxa721     //PXA合约实例
+	pxc    *Pxc20      //PXC合约实例
+	settle *Settlement //结算合约实例
+
+	adminAddr string //管理员地址
+	adminkey  string //管理员keystore文件内容
+	adminPass string //管理员keystore密码
+
+	pxcMoney   *Money     //PXC代币单位，首次使用时从合约读取
+	pxcMoneyMu sync.Mutex //保护pxcMoney
+	hub        *eventHub  //事件订阅分发中心
+}
+
+// 已连接的链，键为链ID
+var chains = map[string]*Chain{}
+
+// 按配置顺序排列的链，第一条为默认链
+var chainList []*Chain
+
+// Connect按配置连接各条链，由main在启动时调用，任一条链连接失败时终止程序
+func Connect() {
+	//1. 按配置连接各条链
+	for _, conf := range configs.ChainConfs() {
+		ch, err := newChain(conf)
+		if err != nil {
+			log.Panic("Failed to connect chain ", conf.Name, " ", err)
+		}
+		if _, ok := chains[ch.ChainID()]; ok {
+			log.Panicf("duplicate chain id %s in config", ch.ChainID())
+		}
+		chains[ch.ChainID()] = ch
+		chainList = append(chainList, ch)
+		fmt.Printf("connected chain %s (%s)\n", ch.Name, ch.ChainID())
+	}
+	//2. 升级前未标记链ID的数据归属默认链
+	if err := dbs.AssignDefaultChain(DefaultChain().ChainID()); err != nil {
+		log.Panic("Failed to assign default chain ", err)
+	}
+}
+
+// 连接一条链：查询链ID，按链ID读取合约地址登记，并加载该链的管理员账户
+func newChain(conf configs.Chain) (*Chain, error) {
+	//1. 初始化以太坊客户端
+	cli, err := ethclient.Dial(conf.RPCURL)
+	if err != nil {
+		fmt.Println("Failed to ethclient.Dial", err)
+		return nil, err
+	}
+	ch := &Chain{
+		Name:       conf.Name,
+		cli:        cli,
+		wsURL:      conf.WSURL,
+		pxaAddr:    DEFAULT_PXA_ADDR,
+		pxcAddr:    DEFAULT_PXC_ADDR,
+		settleAddr: DEFAULT_SETTLE_ADDR,
+		adminPass:  conf.AdminPass,
+	}
+	ch.hub = &eventHub{ch: ch, subs: map[*EventSubscription]struct{}{}}
+	if ch.adminPass == "" {
+		ch.adminPass = DEFAULT_ADMIN_PASS
+	}
+	//2. 按链ID读取合约地址登记
+	if err = ch.loadContracts(); err != nil {
+		return nil, err
+	}
+	if ch.Name == "" {
+		ch.Name = ch.ChainID()
+	}
+	//3. 创建合约实例
+	if ch.pxa, err = NewPxa721(common.HexToAddress(ch.pxaAddr), cli); err != nil {
+		fmt.Println("Failed to NewPxa721", err)
+		return nil, err
+	}
+	if ch.pxc, err = NewPxc20(common.HexToAddress(ch.pxcAddr), cli); err != nil {
+		fmt.Println("Failed to NewPxc20", err)
+		return nil, err
+	}
+	if ch.settle, err = NewSettlement(common.HexToAddress(ch.settleAddr), cli); err != nil {
+		fmt.Println("Failed to NewSettlement", err)
+		return nil, err
+	}
+	//4. 从数据库读取管理员地址，从data目录读取管理员密钥文件
+	admin := conf.Admin
+	if admin == "" {
+		admin = DEFAULT_ADMIN
+	}
+	if ch.adminAddr, err = GetAdminAddrFromDB(admin); err != nil {
+		fmt.Println("从数据库获取管理员地址失败", err)
+		return nil, err
+	}
+	hdks := hdkeystore.NewHDkeyStoreNoKey("./data")
+	keyjson, err := os.ReadFile(hdks.JoinPath(ch.adminAddr))
+	if err != nil {
+		fmt.Println("Failed to read admin key file", err)
+		return nil, err
+	}
+	ch.adminkey = string(keyjson)
+	return ch, nil
+}
+
+// 默认链，请求未指定链时使用
+func DefaultChain() *Chain {
+	return chainList[0]
+}
+
+// 按链ID或链名称查找已连接的链，为空时返回默认链
+func GetChain(id string) (*Chain, error) {
+	if id == "" {
+		return DefaultChain(), nil
+	}
+	if ch, ok := chains[id]; ok {
+		return ch, nil
+	}
+	for _, ch := range chainList {
+		if strings.EqualFold(ch.Name, id) {
+			return ch, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown chain %s", id)
+}
+
+// 全部已连接的链
+func Chains() []*Chain {
+	return chainList
+}
+
+// 链ID的十进制字符串，与t_content.chain_id和合约登记的键一致
+func (ch *Chain) ChainID() string {
+	return ch.ID.String()
+}
+
+// 是否为默认链
+func (ch *Chain) IsDefault() bool {
+	return ch == DefaultChain()
+}
+
+// 链上合约地址
+func (ch *Chain) Contracts() configs.Contracts {
+	return configs.Contracts{
+		PXC20:       common.HexToAddress(ch.pxcAddr).Hex(),
+		PXA721:      common.HexToAddress(ch.pxaAddr).Hex(),
+		Settlement:  common.HexToAddress(ch.settleAddr).Hex(),
+		DeployBlock: ch.deployBlock,
+	}
+}
@@ -0,0 +1,149 @@
+package eths
+
+import (
+	"context"
+	"copyright/configs"
+	"copyright/dbs"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 索引扫描的起始区块：重新扫描未达到确认深度的区块，以便发现链重组后新出现的事件
+func rescanStart(checkpoint uint64) uint64 {
+	if checkpoint+1 < configs.Conf.Confirmations {
+		return 0
+	}
+	return checkpoint + 1 - configs.Conf.Confirmations
+}
+
+// 检查未确认事件所在区块是否仍在主链上，回滚被重组的事件，并确认达到深度的事件
+func (ch *Chain) confirmEvents(contract string, latest uint64) error {
+	blocks, err := dbs.QueryPendingBlocks(ch.ChainID(), contract)
+	if err != nil {
+		return err
+	}
+	for _, b := range blocks {
+		canonical, err := ch.isCanonical(b.Number, b.Hash)
+		if err != nil {
+			return err
+		}
+		if !canonical {
+			fmt.Printf("chain reorg detected at block %d (%s), rollback events of %s\n", b.Number, b.Hash, contract)
+			if err = dbs.DeleteEventsByBlockHash(ch.ChainID(), b.Hash); err != nil {
+				return err
+			}
+		}
+	}
+	if latest < configs.Conf.Confirmations {
+		return nil
+	}
+	return dbs.ConfirmEvents(ch.ChainID(), contract, latest-configs.Conf.Confirmations)
+}
+
+// 交割交易广播后超过该时间仍不在节点的交易池或链上时，视为已丢弃
+const TRADE_DROP_TIMEOUT = 30 * time.Minute
+
+// 跟踪拍卖交割交易的确认状态
+func (ch *Chain) confirmTrades(latest uint64) error {
+	trades, err := dbs.QueryPendingTrades(ch.ChainID())
+	if err != nil {
+		return err
+	}
+	for _, t := range trades {
+		//1. 查询结算交易回执，付款、版税分账和份额转移在同一笔交易中完成
+		receipt, err := ch.tradeReceipt(t.TxHash)
+		if err != nil {
+			return err
+		}
+		// 回执不存在：尚未打包，或所在区块已被重组，清空区块信息等待重新打包
+		if receipt == nil {
+			if t.BlockHash != "" {
+				fmt.Printf("trade %d is no longer in canonical chain, rollback to pending\n", t.ID)
+				t.BlockNumber, t.BlockHash = 0, ""
+				if err = t.UpdateStatus(); err != nil {
+					return err
+				}
+				continue
+			}
+			// 交易哈希在广播前登记，广播失败或交易被节点丢弃时，超时后撤销
+			if err = ch.failDroppedTrade(t); err != nil {
+				return err
+			}
+			continue
+		}
+		t.BlockNumber = receipt.BlockNumber.Uint64()
+		t.BlockHash = receipt.BlockHash.Hex()
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			fmt.Printf("trade %d transaction reverted\n", t.ID)
+			// 交易回滚，撤销下单时预先扣减的挂牌份额和股权登记
+			if err = t.Fail(); err != nil {
+				return err
+			}
+			continue
+		}
+		//2. 达到确认深度后标记为已确认
+		if t.BlockNumber+configs.Conf.Confirmations <= latest {
+			t.Status = dbs.STATUS_CONFIRMED
+		}
+		if err = t.UpdateStatus(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 交割交易既未打包也不在节点交易池中，且超过TRADE_DROP_TIMEOUT时标记为失败
+func (ch *Chain) failDroppedTrade(t dbs.AuctionHis) error {
+	_, _, err := ch.cli.TransactionByHash(context.Background(), common.HexToHash(t.TxHash))
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		fmt.Println("failed to get transaction", err)
+		return err
+	}
+	failed, err := t.FailStale(TRADE_DROP_TIMEOUT)
+	if err != nil {
+		return err
+	}
+	if failed {
+		fmt.Printf("trade %d transaction %s was dropped, rollback reservation\n", t.ID, t.TxHash)
+	}
+	return nil
+}
+
+// 查询交易回执，交易未打包时返回nil
+func (ch *Chain) tradeReceipt(txHash string) (*types.Receipt, error) {
+	receipt, err := ch.cli.TransactionReceipt(context.Background(), common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		fmt.Println("failed to get transaction receipt", err)
+		return nil, err
+	}
+	canonical, err := ch.isCanonical(receipt.BlockNumber.Uint64(), receipt.BlockHash.Hex())
+	if err != nil || !canonical {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// 判断指定高度的区块哈希是否与主链一致
+func (ch *Chain) isCanonical(number uint64, hash string) (bool, error) {
+	header, err := ch.cli.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+	if err != nil {
+		fmt.Println("failed to get block header", err)
+		return false, err
+	}
+	return header.Hash() == common.HexToHash(hash), nil
+}
@@ -0,0 +1,155 @@
+package eths
+
+import (
+	"context"
+	"copyright/configs"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 合约构造参数，与web-ui/scripts/setup.cjs保持一致
+const (
+	PXC_NAME   = "CopyrightToken"
+	PXC_SYMBOL = "CPT"
+	PXA_NAME   = "CopyrightNFT"
+)
+
+// 部署结果
+type Deployment struct {
+	ChainID   string            //链ID
+	Contracts configs.Contracts //合约地址及部署区块
+	FundTx    string            //给管理员转账的交易哈希
+}
+
+// 部署ERC20、ERC721和结算合约，给管理员转入fund数量的以太币(wei)，并将合约地址登记到当前链ID下
+// deployerKey为有以太币余额的账户私钥（十六进制）
+func (ch *Chain) Deploy(deployerKey string, fund *big.Int) (*Deployment, error) {
+	//1. 加载部署账户
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(deployerKey, "0x"))
+	if err != nil {
+		fmt.Println("invalid deployer key", err)
+		return nil, err
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, ch.ID)
+	if err != nil {
+		fmt.Println("failed to create transactor: ", err)
+		return nil, err
+	}
+	d := &Deployment{ChainID: ch.ID.String()}
+
+	//2. 部署ERC20合约
+	_, tx, _, err := DeployPxc20(auth, ch.cli, PXC_NAME, PXC_SYMBOL)
+	if err != nil {
+		fmt.Println("failed to DeployPxc20", err)
+		return nil, err
+	}
+	receipt, err := ch.waitReceipt(tx)
+	if err != nil {
+		return nil, err
+	}
+	d.Contracts.PXC20 = receipt.ContractAddress.Hex()
+	d.Contracts.DeployBlock = receipt.BlockNumber.Uint64()
+	fmt.Println("ERC20 deployed to:", d.Contracts.PXC20)
+
+	//3. 部署ERC721合约
+	_, tx, _, err = DeployPxa721(auth, ch.cli, PXA_NAME)
+	if err != nil {
+		fmt.Println("failed to DeployPxa721", err)
+		return nil, err
+	}
+	receipt, err = ch.waitReceipt(tx)
+	if err != nil {
+		return nil, err
+	}
+	d.Contracts.PXA721 = receipt.ContractAddress.Hex()
+	fmt.Println("ERC721 deployed to:", d.Contracts.PXA721)
+
+	//4. 部署结算合约，由管理员发起结算
+	_, tx, _, err = DeploySettlement(auth, ch.cli, common.HexToAddress(d.Contracts.PXC20), common.HexToAddress(d.Contracts.PXA721), common.HexToAddress(ch.adminAddr))
+	if err != nil {
+		fmt.Println("failed to DeploySettlement", err)
+		return nil, err
+	}
+	receipt, err = ch.waitReceipt(tx)
+	if err != nil {
+		return nil, err
+	}
+	d.Contracts.Settlement = receipt.ContractAddress.Hex()
+	fmt.Println("Settlement deployed to:", d.Contracts.Settlement)
+
+	//5. 给管理员转账以太币，用于支付上传和交割的gas
+	if fund != nil && fund.Sign() > 0 {
+		tx, err = ch.fundAdmin(key, fund)
+		if err != nil {
+			return nil, err
+		}
+		d.FundTx = tx.Hash().Hex()
+		fmt.Printf("transferred %s wei to admin %s\n", fund, ch.adminAddr)
+	}
+
+	//6. 登记合约地址
+	if err = configs.SaveContracts(d.ChainID, d.Contracts); err != nil {
+		fmt.Println("failed to save contracts to config", err)
+		return nil, err
+	}
+	return d, nil
+}
+
+// 部署账户向管理员地址转账
+func (ch *Chain) fundAdmin(key *ecdsa.PrivateKey, value *big.Int) (*types.Transaction, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := ch.cli.PendingNonceAt(context.Background(), from)
+	if err != nil {
+		fmt.Println("failed to get nonce", err)
+		return nil, err
+	}
+	gasPrice, err := ch.cli.SuggestGasPrice(context.Background())
+	if err != nil {
+		fmt.Println("failed to SuggestGasPrice", err)
+		return nil, err
+	}
+	to := common.HexToAddress(ch.adminAddr)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(ch.ID), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Value:    value,
+		Gas:      21000,
+		GasPrice: gasPrice,
+	})
+	if err != nil {
+		fmt.Println("Failed to SignTx", err)
+		return nil, err
+	}
+	if err = ch.cli.SendTransaction(context.Background(), tx); err != nil {
+		fmt.Println("failed to send fund transaction", err)
+		return nil, err
+	}
+	if _, err = ch.waitReceipt(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// 等待交易打包并检查执行结果
+func (ch *Chain) waitReceipt(tx *types.Transaction) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), TX_WAIT_TIMEOUT)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, ch.cli, tx)
+	if err != nil {
+		fmt.Println("failed to wait transaction mined", err)
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		fmt.Println("transaction reverted", tx.Hash().Hex())
+		return nil, errors.New("transaction reverted")
+	}
+	return receipt, nil
+}
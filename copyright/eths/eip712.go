@@ -0,0 +1,159 @@
+package eths
+
+import (
+	"copyright/hdwallet"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712域名称和版本
+const (
+	EIP712_NAME    = "Geth-copyright"
+	EIP712_VERSION = "1"
+)
+
+// EIP-712类型定义：卖家挂牌和买家订单
+var eip712Types = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"Listing": {
+		{Name: "seller", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "weight", Type: "uint256"},
+		{Name: "price", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+	},
+	"Order": {
+		{Name: "buyer", Type: "address"},
+		{Name: "seller", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "weight", Type: "uint256"},
+		{Name: "price", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+	},
+}
+
+// 卖家挂牌：以price（PXC最小单位）的单价出售tokenId的weight份额
+type Listing struct {
+	Seller  string
+	TokenID string
+	Weight  int64
+	Price   *big.Int
+	Nonce   string
+}
+
+// 买家订单：按挂牌单价购买weight份额
+type Order struct {
+	Buyer   string
+	Seller  string
+	TokenID string
+	Weight  int64
+	Price   *big.Int
+	Nonce   string
+}
+
+// 签名域，绑定当前链ID和ERC721合约地址，防止跨链、跨合约重放
+func (ch *Chain) eip712Domain() apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              EIP712_NAME,
+		Version:           EIP712_VERSION,
+		ChainId:           (*math.HexOrDecimal256)(ch.ID),
+		VerifyingContract: common.HexToAddress(ch.pxaAddr).Hex(),
+	}
+}
+
+// 挂牌在ch链上的EIP-712结构化数据，客户端钱包可直接用于eth_signTypedData_v4
+func (l Listing) TypedData(ch *Chain) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       eip712Types,
+		PrimaryType: "Listing",
+		Domain:      ch.eip712Domain(),
+		Message: apitypes.TypedDataMessage{
+			"seller":  common.HexToAddress(l.Seller).Hex(),
+			"tokenId": l.TokenID,
+			"weight":  fmt.Sprint(l.Weight),
+			"price":   l.Price.String(),
+			"nonce":   l.Nonce,
+		},
+	}
+}
+
+// 订单在ch链上的EIP-712结构化数据
+func (o Order) TypedData(ch *Chain) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       eip712Types,
+		PrimaryType: "Order",
+		Domain:      ch.eip712Domain(),
+		Message: apitypes.TypedDataMessage{
+			"buyer":   common.HexToAddress(o.Buyer).Hex(),
+			"seller":  common.HexToAddress(o.Seller).Hex(),
+			"tokenId": o.TokenID,
+			"weight":  fmt.Sprint(o.Weight),
+			"price":   o.Price.String(),
+			"nonce":   o.Nonce,
+		},
+	}
+}
+
+// 使用账户的keystore对结构化数据签名，返回十六进制签名
+func SignTypedData(from, pass string, data apitypes.TypedData) (string, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		fmt.Println("failed to hash typed data", err)
+		return "", err
+	}
+	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
+	if err != nil {
+		fmt.Println("failed to LoadWalletByPass", err)
+		return "", err
+	}
+	sig, err := w.HdKeyStore.SignHash(hash)
+	if err != nil {
+		fmt.Println("failed to sign typed data", err)
+		return "", err
+	}
+	return hexutil.Encode(sig), nil
+}
+
+// 校验结构化数据的签名是否由signer签署
+func VerifyTypedData(data apitypes.TypedData, signature, signer string) error {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		fmt.Println("failed to hash typed data", err)
+		return err
+	}
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return errors.New("invalid signature format")
+	}
+	// 兼容v为27/28和0/1两种格式
+	sig = common.CopyBytes(sig)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		fmt.Println("failed to recover signer", err)
+		return err
+	}
+	if crypto.PubkeyToAddress(*pub) != common.HexToAddress(signer) {
+		return errors.New("signature does not match signer")
+	}
+	return nil
+}
+
+// 签名域和类型定义，供客户端钱包构造待签名数据
+func (ch *Chain) EIP712Schema() (apitypes.TypedDataDomain, apitypes.Types) {
+	return ch.eip712Domain(), eip712Types
+}
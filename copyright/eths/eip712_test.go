@@ -0,0 +1,193 @@
+package eths
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712规范中的Mail示例，签名者为私钥keccak256("cow")对应的地址
+func TestVerifyTypedDataSpecExample(t *testing.T) {
+	data := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainId:           math.NewHexOrDecimal256(1),
+			VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!",
+		},
+	}
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexutil.Encode(hash); got != "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Fatalf("typed data hash = %s", got)
+	}
+	// r || s || v，v为27/28格式
+	sig := "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	if err = VerifyTypedData(data, sig, "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"); err != nil {
+		t.Errorf("spec signature rejected: %v", err)
+	}
+	if err = VerifyTypedData(data, sig, "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"); err == nil {
+		t.Error("spec signature accepted for another signer")
+	}
+}
+
+// 测试用的链，只设置签名域需要的链ID和ERC721合约地址
+func testChain(chainID int64) *Chain {
+	return &Chain{ID: big.NewInt(chainID), pxaAddr: DEFAULT_PXA_ADDR}
+}
+
+// 按SignTypedData的方式对结构化数据签名，v为0/1格式
+func signTypedData(t *testing.T, key *ecdsa.PrivateKey, data apitypes.TypedData) string {
+	t.Helper()
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(sig)
+}
+
+func testKey(t *testing.T, seed string) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte(seed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey).Hex()
+}
+
+func TestListingSignature(t *testing.T) {
+	ch := testChain(31337)
+	key, seller := testKey(t, "seller")
+	listing := Listing{
+		Seller:  seller,
+		TokenID: "202601011200000001",
+		Weight:  30,
+		Price:   new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17)),
+		Nonce:   "1",
+	}
+	sig := signTypedData(t, key, listing.TypedData(ch))
+	if err := VerifyTypedData(listing.TypedData(ch), sig, seller); err != nil {
+		t.Fatalf("valid listing signature rejected: %v", err)
+	}
+	// 钱包返回的v为27/28时同样有效
+	raw := hexutil.MustDecode(sig)
+	raw[crypto.RecoveryIDOffset] += 27
+	if err := VerifyTypedData(listing.TypedData(ch), hexutil.Encode(raw), seller); err != nil {
+		t.Errorf("signature with v in 27/28 rejected: %v", err)
+	}
+	// 挂牌数据、签名域或签名者任一不同都不能通过校验
+	tampered := map[string]Listing{}
+	l := listing
+	l.Price = new(big.Int).Add(listing.Price, big.NewInt(1))
+	tampered["price"] = l
+	l = listing
+	l.Weight = 31
+	tampered["weight"] = l
+	l = listing
+	l.TokenID = "202601011200000002"
+	tampered["token"] = l
+	l = listing
+	l.Nonce = "2"
+	tampered["nonce"] = l
+	for name, l := range tampered {
+		if err := VerifyTypedData(l.TypedData(ch), sig, seller); err == nil {
+			t.Errorf("signature accepted after changing %s", name)
+		}
+	}
+	if err := VerifyTypedData(listing.TypedData(testChain(1)), sig, seller); err == nil {
+		t.Error("signature accepted on another chain")
+	}
+	other := &Chain{ID: big.NewInt(31337), pxaAddr: DEFAULT_PXC_ADDR}
+	if err := VerifyTypedData(listing.TypedData(other), sig, seller); err == nil {
+		t.Error("signature accepted for another contract")
+	}
+	_, buyer := testKey(t, "buyer")
+	if err := VerifyTypedData(listing.TypedData(ch), sig, buyer); err == nil {
+		t.Error("signature accepted for another signer")
+	}
+}
+
+func TestOrderSignature(t *testing.T) {
+	ch := testChain(31337)
+	key, buyer := testKey(t, "buyer")
+	_, seller := testKey(t, "seller")
+	order := Order{
+		Buyer:   buyer,
+		Seller:  seller,
+		TokenID: "202601011200000001",
+		Weight:  10,
+		Price:   big.NewInt(1e18),
+		Nonce:   "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+	}
+	sig := signTypedData(t, key, order.TypedData(ch))
+	if err := VerifyTypedData(order.TypedData(ch), sig, buyer); err != nil {
+		t.Fatalf("valid order signature rejected: %v", err)
+	}
+	// 小写地址与校验和地址表示同一签名者
+	if err := VerifyTypedData(order.TypedData(ch), sig, strings.ToLower(buyer)); err != nil {
+		t.Errorf("signature rejected for lowercase signer: %v", err)
+	}
+	o := order
+	o.Seller = buyer
+	if err := VerifyTypedData(o.TypedData(ch), sig, buyer); err == nil {
+		t.Error("signature accepted after changing seller")
+	}
+	// 挂牌签名不能当作订单签名使用
+	listing := Listing{Seller: buyer, TokenID: order.TokenID, Weight: order.Weight, Price: order.Price, Nonce: order.Nonce}
+	if err := VerifyTypedData(listing.TypedData(ch), sig, buyer); err == nil {
+		t.Error("order signature accepted as a listing")
+	}
+}
+
+func TestVerifyTypedDataMalformed(t *testing.T) {
+	ch := testChain(31337)
+	_, seller := testKey(t, "seller")
+	listing := Listing{Seller: seller, TokenID: "1", Weight: 1, Price: big.NewInt(1), Nonce: "1"}
+	for _, sig := range []string{"", "0x", "0x1234", "not hex", "0x" + strings.Repeat("zz", 65)} {
+		if err := VerifyTypedData(listing.TypedData(ch), sig, seller); err == nil {
+			t.Errorf("malformed signature %q accepted", sig)
+		}
+	}
+	// nonce超出uint256时无法计算哈希
+	listing.Nonce = "115792089237316195423570985008687907853269984665640564039457584007913129639936"
+	if err := VerifyTypedData(listing.TypedData(ch), "0x"+common.Bytes2Hex(make([]byte, 65)), seller); err == nil {
+		t.Error("listing with nonce overflowing uint256 accepted")
+	}
+}
@@ -3,93 +3,29 @@ package eths
 import (
 	"context"
 	"copyright/dbs"
-	"copyright/hdkeystore"
 	"copyright/hdwallet"
+	"copyright/utils"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
-	"os"
 	"strings"
+	"time"
 
-	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// ERC721合约地址
-var PXA_ADDR = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
-
-// ERC20合约地址
-var PXC_ADDR = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
-
-// Geth客户端全局连接句柄
-var ethcli *ethclient.Client
-
-// PXA合约全局实例
-var instancePXA *Pxa721
-
-// PXC合约全局实例
-var instancePXC *Pxc20
-
-// 管理员相关变量
-var adminAddr string
-var adminkey string
-
-func init() {
-	// 初始化以太坊客户端和智能合约实例
-	cli, err := ethclient.Dial("http://localhost:8545")
-	if err != nil {
-		log.Panic("Failed to ethclient.Dial ", err)
-	}
-	ethcli = cli
-	instance, err := NewPxa721(common.HexToAddress(PXA_ADDR), cli)
-	if err != nil {
-		log.Panic("Failed to NewPxa721", err)
-	}
-	instancePXA = instance
-	ins, err := NewPxc20(common.HexToAddress(PXC_ADDR), cli)
-	if err != nil {
-		log.Panic("Failed to NewPxc20", err)
-	}
-	instancePXC = ins
-
-	// 从数据库初始化管理员地址
-	dbAdminAddr, err := GetAdminAddrFromDB()
-	if err != nil {
-		log.Panicf("从数据库获取管理员地址失败: %v", err)
-		os.Exit(1)
-	} else {
-		adminAddr = dbAdminAddr
-		// fmt.Println("成功取得管理员地址", adminAddr)
-	}
-
-	// 直接从data目录读取管理员密钥文件并赋值给adminkey
-	// 创建HDkeyStore实例
-	hdks := hdkeystore.NewHDkeyStoreNoKey("./data")
-	// 获取密钥文件的完整路径
-	keyFilePath := hdks.JoinPath(dbAdminAddr)
-	// 读取密钥文件内容
-	keyjson, err := os.ReadFile(keyFilePath)
-	if err != nil {
-		log.Panic("Failed to read admin key file: ", err)
-	}
-	// 将密钥文件内容赋值给adminkey
-	adminkey = string(keyjson)
-	// fmt.Println("成功取得管理员密钥", adminkey)
-}
-
 // 上传图片调用
-func UploadPic(from, pass, to string, tokenid *big.Int) error {
+func (ch *Chain) UploadPic(from, pass, to string, tokenid *big.Int) error {
 	//3. 设置签名 -- 需要owner的keystore文件
 	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
 	if err != nil {
 		fmt.Println("failed to LoadWalletByPass", err)
 		return err
 	}
-	chainId, err := ethcli.ChainID(context.Background())
+	chainId, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("Failed to get chainId", err)
 		return err
@@ -104,7 +40,7 @@ func UploadPic(from, pass, to string, tokenid *big.Int) error {
 		return fmt.Errorf("failed to create valid auth object")
 	}
 	//4. 调用
-	_, err = instancePXA.UploadMint(auth, common.HexToAddress(to), tokenid)
+	_, err = ch.pxa.UploadMint(auth, common.HexToAddress(to), tokenid)
 	if err != nil {
 		fmt.Println("failed to UploadMint  ", err)
 		return err
@@ -112,10 +48,29 @@ func UploadPic(from, pass, to string, tokenid *big.Int) error {
 	return nil
 }
 
+// 由管理员代为铸造，审核通过的上传使用（上传用户的keystore密码不在审核人手中），等待交易打包后返回
+func (ch *Chain) AdminUploadPic(to string, tokenid *big.Int) (*types.Transaction, error) {
+	keyin := strings.NewReader(ch.adminkey)
+	auth, err := bind.NewTransactorWithChainID(keyin, ch.adminPass, ch.ID)
+	if err != nil {
+		fmt.Println("failed to create transactor: ", err)
+		return nil, err
+	}
+	tx, err := ch.pxa.UploadMint(auth, common.HexToAddress(to), tokenid)
+	if err != nil {
+		fmt.Println("failed to UploadMint  ", err)
+		return nil, err
+	}
+	if _, err = ch.waitReceipt(tx); err != nil {
+		return tx, err
+	}
+	return tx, nil
+}
+
 // 从数据库获取管理员地址
-func GetAdminAddrFromDB() (string, error) {
+func GetAdminAddrFromDB(username string) (string, error) {
 	var user dbs.User
-	found, err := user.QueryByUsername("admin")
+	found, err := user.QueryByUsername(username)
 	if err != nil {
 		return "", fmt.Errorf("查询管理员用户失败: %v", err)
 	}
@@ -125,8 +80,13 @@ func GetAdminAddrFromDB() (string, error) {
 	return user.Address, nil
 }
 
+// 平台（管理员）地址，作为默认的授权对象
+func (ch *Chain) MarketAddress() string {
+	return ch.adminAddr
+}
+
 // 授权
-func SetApprove(from, pass string, tokenid *big.Int) error {
+func (ch *Chain) SetApprove(from, pass string, tokenid *big.Int) error {
 
 	//3. 设置签名 -- 需要owner的keystore文件
 	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
@@ -134,14 +94,14 @@ func SetApprove(from, pass string, tokenid *big.Int) error {
 		fmt.Println("failed to LoadWalletByPass", err)
 		return err
 	}
-	chainId, err := ethcli.ChainID(context.Background())
+	chainId, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("Failed to get chainId", err)
 		return err
 	}
 	auth, _ := w.HdKeyStore.NewTransactOpts(chainId)
 	//4. 调用
-	_, err = instancePXA.Approve(auth, common.HexToAddress(adminAddr), tokenid)
+	_, err = ch.pxa.Approve(auth, common.HexToAddress(ch.adminAddr), tokenid)
 	if err != nil {
 		fmt.Println("failed to Approve  ", err)
 		return err
@@ -150,7 +110,7 @@ func SetApprove(from, pass string, tokenid *big.Int) error {
 }
 
 // 取消授权
-func CancelApprove(from, pass string, tokenid *big.Int) error {
+func (ch *Chain) CancelApprove(from, pass string, tokenid *big.Int) error {
 
 	//1. 设置签名 -- 需要owner的keystore文件
 	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
@@ -158,14 +118,14 @@ func CancelApprove(from, pass string, tokenid *big.Int) error {
 		fmt.Println("failed to LoadWalletByPass", err)
 		return err
 	}
-	chainId, err := ethcli.ChainID(context.Background())
+	chainId, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("Failed to get chainId", err)
 		return err
 	}
 	auth, _ := w.HdKeyStore.NewTransactOpts(chainId)
 	//2. 调用 - 通过将授权地址设置为0地址来取消授权
-	_, err = instancePXA.Approve(auth, common.HexToAddress("0x0000000000000000000000000000000000000000"), tokenid)
+	_, err = ch.pxa.Approve(auth, common.HexToAddress("0x0000000000000000000000000000000000000000"), tokenid)
 	if err != nil {
 		fmt.Println("failed to CancelApprove  ", err)
 		return err
@@ -173,8 +133,46 @@ func CancelApprove(from, pass string, tokenid *big.Int) error {
 	return nil
 }
 
+// 批量授权：授权operator管理from名下的全部erc721
+func (ch *Chain) SetApprovalForAll(from, pass string, operator string) (*types.Transaction, error) {
+
+	//1. 设置签名 -- 需要owner的keystore文件
+	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
+	if err != nil {
+		fmt.Println("failed to LoadWalletByPass", err)
+		return nil, err
+	}
+	chainId, err := ch.cli.ChainID(context.Background())
+	if err != nil {
+		fmt.Println("Failed to get chainId", err)
+		return nil, err
+	}
+	auth, err := w.HdKeyStore.NewTransactOpts(chainId)
+	if err != nil {
+		fmt.Println("Failed to NewTransactOpts", err)
+		return nil, err
+	}
+	//2. 调用
+	tx, err := ch.pxa.SetApprovalForAll(auth, common.HexToAddress(operator), true)
+	if err != nil {
+		fmt.Println("failed to SetApprovalForAll  ", err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// 查询owner是否已批量授权给operator
+func (ch *Chain) IsApprovedForAll(owner, operator string) (bool, error) {
+	approved, err := ch.pxa.IsApprovedForAll(&bind.CallOpts{}, common.HexToAddress(owner), common.HexToAddress(operator))
+	if err != nil {
+		fmt.Println("failed to IsApprovedForAll", err)
+		return false, err
+	}
+	return approved, nil
+}
+
 // 取消批量授权
-func CancelApprovalForAll(from, pass string, operator string) error {
+func (ch *Chain) CancelApprovalForAll(from, pass string, operator string) error {
 
 	//1. 设置签名 -- 需要owner的keystore文件
 	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
@@ -182,14 +180,14 @@ func CancelApprovalForAll(from, pass string, operator string) error {
 		fmt.Println("failed to LoadWalletByPass", err)
 		return err
 	}
-	chainId, err := ethcli.ChainID(context.Background())
+	chainId, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("Failed to get chainId", err)
 		return err
 	}
 	auth, _ := w.HdKeyStore.NewTransactOpts(chainId)
 	//2. 调用 - 通过将approved设置为false来取消批量授权
-	_, err = instancePXA.SetApprovalForAll(auth, common.HexToAddress(operator), false)
+	_, err = ch.pxa.SetApprovalForAll(auth, common.HexToAddress(operator), false)
 	if err != nil {
 		fmt.Println("failed to CancelApprovalForAll  ", err)
 		return err
@@ -198,64 +196,230 @@ func CancelApprovalForAll(from, pass string, operator string) error {
 }
 
 // 转移erc20
-func TransferPXC(from, pass, to string, value *big.Int) error {
+func (ch *Chain) TransferPXC(from, pass, to string, value *big.Int) (*types.Transaction, error) {
 	//1. 钱包加载
 	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
 	if err != nil {
 		fmt.Println("failed to LoadWalletByPass", err)
-		return err
+		return nil, err
 	}
 	//2. 获取chainId
-	chainId, err := ethcli.ChainID(context.Background())
+	chainId, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("Failed to get chainId", err)
-		return err
+		return nil, err
 	}
 	//3. 设置签名
 	auth, err := w.HdKeyStore.NewTransactOpts(chainId)
 	if err != nil {
 		fmt.Println("Failed to NewTransactOpts", err)
-		return err
+		return nil, err
 	}
 	//4. 调用
-	_, err = instancePXC.Transfer(auth, common.HexToAddress(to), value)
+	tx, err := ch.pxc.Transfer(auth, common.HexToAddress(to), value)
 	if err != nil {
 		fmt.Println("failed to Transfer  ", err)
-		return err
+		return nil, err
 	}
-	return nil
+	return tx, nil
+}
+
+// 授权spender从from账户划转erc20，value为授权额度（覆盖原额度）
+func (ch *Chain) ApprovePXC(from, pass, spender string, value *big.Int) (*types.Transaction, error) {
+	//1. 钱包加载
+	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
+	if err != nil {
+		fmt.Println("failed to LoadWalletByPass", err)
+		return nil, err
+	}
+	//2. 获取chainId
+	chainId, err := ch.cli.ChainID(context.Background())
+	if err != nil {
+		fmt.Println("Failed to get chainId", err)
+		return nil, err
+	}
+	//3. 设置签名
+	auth, err := w.HdKeyStore.NewTransactOpts(chainId)
+	if err != nil {
+		fmt.Println("Failed to NewTransactOpts", err)
+		return nil, err
+	}
+	//4. 调用
+	tx, err := ch.pxc.Approve(auth, common.HexToAddress(spender), value)
+	if err != nil {
+		fmt.Println("failed to Approve PXC  ", err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// 查询owner授权给spender的erc20剩余额度
+func (ch *Chain) AllowancePXC(owner, spender string) (*big.Int, error) {
+	value, err := ch.pxc.Allowance(&bind.CallOpts{}, common.HexToAddress(owner), common.HexToAddress(spender))
+	if err != nil {
+		fmt.Println("failed to get PXC allowance", err)
+		return nil, err
+	}
+	return value, nil
+}
+
+// spender使用owner的授权额度将erc20划转给to
+func (ch *Chain) TransferFromPXC(spender, pass, owner, to string, value *big.Int) (*types.Transaction, error) {
+	//1. 钱包加载
+	w, err := hdwallet.LoadWalletByPass(spender, "./data", pass)
+	if err != nil {
+		fmt.Println("failed to LoadWalletByPass", err)
+		return nil, err
+	}
+	//2. 获取chainId
+	chainId, err := ch.cli.ChainID(context.Background())
+	if err != nil {
+		fmt.Println("Failed to get chainId", err)
+		return nil, err
+	}
+	//3. 设置签名
+	auth, err := w.HdKeyStore.NewTransactOpts(chainId)
+	if err != nil {
+		fmt.Println("Failed to NewTransactOpts", err)
+		return nil, err
+	}
+	//4. 调用
+	tx, err := ch.pxc.TransferFrom(auth, common.HexToAddress(owner), common.HexToAddress(to), value)
+	if err != nil {
+		fmt.Println("failed to TransferFrom PXC  ", err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// 等待交易打包的超时时间
+const TX_WAIT_TIMEOUT = 30 * time.Second
+
+// 整体转让原始erc721，由owner使用自己的keystore签名：合约的标准transferFrom只转移原始token、不转移拆分份额，
+// 因此以全部100份额调用partTransferFrom，合约在接收方凑满100份时同时转移原始token所有权，
+// 份额和所有权在同一笔交易中完成，等待交易打包后返回
+func (ch *Chain) TransferPXA(from, pass, to string, tokenid, price *big.Int) (*types.Transaction, error) {
+	//1. 钱包加载
+	w, err := hdwallet.LoadWalletByPass(from, "./data", pass)
+	if err != nil {
+		fmt.Println("failed to LoadWalletByPass", err)
+		return nil, err
+	}
+	//2. 获取chainId
+	chainId, err := ch.cli.ChainID(context.Background())
+	if err != nil {
+		fmt.Println("Failed to get chainId", err)
+		return nil, err
+	}
+	//3. 设置签名
+	auth, err := w.HdKeyStore.NewTransactOpts(chainId)
+	if err != nil {
+		fmt.Println("Failed to NewTransactOpts", err)
+		return nil, err
+	}
+	//4. 调用
+	tx, err := ch.pxa.PartTransferFrom(auth, common.HexToAddress(from), common.HexToAddress(to), tokenid, big.NewInt(100), price)
+	if err != nil {
+		fmt.Println("failed to TransferPXA  ", err)
+		return nil, err
+	}
+	//5. 等待打包，交易回滚时不更新数据库
+	if _, err = ch.waitReceipt(tx); err != nil {
+		return tx, err
+	}
+	return tx, nil
 }
 
 // 转移erc721
-func PartTransferPXA(from, to string, tokenid, weight, price *big.Int) error {
+func (ch *Chain) PartTransferPXA(from, to string, tokenid, weight, price *big.Int) (*types.Transaction, error) {
 	//3. 设置签名 -- 需要owner的keystore文件
-	keyin := strings.NewReader(adminkey)
-	chainID, err := ethcli.ChainID(context.Background())
+	keyin := strings.NewReader(ch.adminkey)
+	chainID, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("failed to ChainID  ", err)
-		return err
+		return nil, err
 	}
 	// 修复：正确处理NewTransactorWithChainID可能返回的错误
-	auth, err := bind.NewTransactorWithChainID(keyin, "1234", chainID)
+	auth, err := bind.NewTransactorWithChainID(keyin, ch.adminPass, chainID)
 	if err != nil {
 		fmt.Println("failed to create transactor: ", err)
-		return err
+		return nil, err
 	}
 	//4. 调用
-	_, err = instancePXA.PartTransferFrom(auth, common.HexToAddress(from), common.HexToAddress(to), tokenid, weight, price)
+	tx, err := ch.pxa.PartTransferFrom(auth, common.HexToAddress(from), common.HexToAddress(to), tokenid, weight, price)
 	if err != nil {
 		fmt.Println("failed to TransferPXA  ", err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// 结算合约地址，买家需授权该地址划转PXC
+func (ch *Chain) SettlementAddress() string {
+	return ch.settleAddr
+}
+
+// 买家对结算合约的PXC授权额度不足本次总价
+var ErrAllowanceInsufficient = errors.New("insufficient PXC allowance for settlement")
+
+// 通过结算合约原子完成一笔份额交易：买家付款给卖家、版税付给创作者、份额转给买家，任一步失败整体回滚
+// price为每份单价（最小单位），royalty为从总价中分给创作者的金额，creator为空或royalty为0时不分账
+// 买家须事先授权结算合约划转不少于本次总价的PXC，不会覆盖买家已有的授权额度；
+// 返回的交易只签名、不广播，调用方记录交易哈希后再通过SendTransaction广播
+func (ch *Chain) SettleTrade(buyer, seller, creator string, tokenid, weight, price, royalty *big.Int) (*types.Transaction, error) {
+	//1. 校验买家授权额度
+	total := new(big.Int).Mul(weight, price)
+	allowance, err := ch.AllowancePXC(buyer, ch.settleAddr)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(total) < 0 {
+		fmt.Println("settlement allowance is insufficient", buyer, allowance, total)
+		return nil, ErrAllowanceInsufficient
+	}
+	//2. 设置签名 -- 结算只能由平台管理员发起
+	keyin := strings.NewReader(ch.adminkey)
+	chainID, err := ch.cli.ChainID(context.Background())
+	if err != nil {
+		fmt.Println("failed to ChainID  ", err)
+		return nil, err
+	}
+	auth, err := bind.NewTransactorWithChainID(keyin, ch.adminPass, chainID)
+	if err != nil {
+		fmt.Println("failed to create transactor: ", err)
+		return nil, err
+	}
+	auth.NoSend = true
+	//3. 调用
+	if creator == "" {
+		creator = seller
+	}
+	if royalty == nil {
+		royalty = big.NewInt(0)
+	}
+	tx, err := ch.settle.Settle(auth, common.HexToAddress(buyer), common.HexToAddress(seller), tokenid, weight, price, common.HexToAddress(creator), royalty)
+	if err != nil {
+		fmt.Println("failed to Settle  ", err)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// 广播已签名的交易
+func (ch *Chain) SendTransaction(tx *types.Transaction) error {
+	if err := ch.cli.SendTransaction(context.Background(), tx); err != nil {
+		fmt.Println("failed to send transaction", tx.Hash().Hex(), err)
 		return err
 	}
 	return nil
 }
 
 // 获取Token所有者
-func GetTokenOwner(tokenID *big.Int) (common.Address, error) {
+func (ch *Chain) GetTokenOwner(tokenID *big.Int) (common.Address, error) {
 	// 创建一个call选项，使用默认值
 	callOpts := &bind.CallOpts{}
 	// 调用合约的OwnerOf方法
-	owner, err := instancePXA.OwnerOf(callOpts, tokenID)
+	owner, err := ch.pxa.OwnerOf(callOpts, tokenID)
 	if err != nil {
 		fmt.Println("Failed to get token owner", err)
 		return common.Address{}, err
@@ -263,22 +427,75 @@ func GetTokenOwner(tokenID *big.Int) (common.Address, error) {
 	return owner, nil
 }
 
+// 查询token的铸造事件，优先使用事件索引，索引器尚未扫描到时直接查询链上日志，found为false表示尚未铸造
+func (ch *Chain) MintEvent(tokenID *big.Int) (e dbs.ChainEvent, found bool, err error) {
+	//1. 事件索引
+	zero := common.Address{}
+	e, found, err = dbs.QueryMintEvent(ch.ChainID(), common.HexToAddress(ch.pxaAddr).Hex(), tokenID.String(), zero.Hex())
+	if err != nil || found {
+		return e, found, err
+	}
+	//2. 链上日志
+	transfers, err := ch.pxa.FilterTransfer(&bind.FilterOpts{Start: ch.deployBlock}, []common.Address{zero}, nil, []*big.Int{tokenID})
+	if err != nil {
+		fmt.Println("failed to FilterTransfer of PXA721", err)
+		return e, false, err
+	}
+	defer transfers.Close()
+	if transfers.Next() {
+		e = pxa721TransferEvent(transfers.Event)
+		e.ChainID = ch.ChainID()
+		return e, true, nil
+	}
+	if err = transfers.Error(); err != nil {
+		fmt.Println("failed to iterate Transfer of PXA721", err)
+		return e, false, err
+	}
+	return e, false, nil
+}
+
+// 查询持有人在链上的拆分token id及份额
+func (ch *Chain) GetShares(orgTokenID *big.Int, owner string) (*big.Int, *big.Int, error) {
+	callOpts := &bind.CallOpts{}
+	splitID, err := ch.pxa.GetSplitToken(callOpts, orgTokenID, common.HexToAddress(owner))
+	if err != nil {
+		fmt.Println("Failed to GetSplitToken", err)
+		return nil, nil, err
+	}
+	asset, err := ch.pxa.TokenSplitAsset(callOpts, splitID)
+	if err != nil {
+		fmt.Println("Failed to get _tokenSplitAsset", err)
+		return nil, nil, err
+	}
+	return splitID, asset.Weight, nil
+}
+
+// 查询原始token最近一次成交的百分比单价
+func (ch *Chain) GetTokenPrice(orgTokenID *big.Int) (*big.Int, error) {
+	price, err := ch.pxa.TokenPrice(&bind.CallOpts{}, orgTokenID)
+	if err != nil {
+		fmt.Println("Failed to get _tokenPrice", err)
+		return nil, err
+	}
+	return price, nil
+}
+
 // 代币发放(Mint)
-func MintToken(to string, value *big.Int) error {
+func (ch *Chain) MintToken(to string, value *big.Int) error {
 	// 使用管理员身份创建交易选项
-	keyin := strings.NewReader(adminkey)
-	chainID, err := ethcli.ChainID(context.Background())
+	keyin := strings.NewReader(ch.adminkey)
+	chainID, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		fmt.Println("failed to get chainID: ", err)
 		return err
 	}
-	auth, err := bind.NewTransactorWithChainID(keyin, "1234", chainID)
+	auth, err := bind.NewTransactorWithChainID(keyin, ch.adminPass, chainID)
 	if err != nil {
 		fmt.Println("failed to create transactor: ", err)
 		return err
 	}
 	// 调用Pxc20合约的Mint方法
-	_, err = instancePXC.Mint(auth, common.HexToAddress(to), value)
+	_, err = ch.pxc.Mint(auth, common.HexToAddress(to), value)
 	if err != nil {
 		fmt.Println("failed to mint token: ", err)
 		return err
@@ -288,9 +505,9 @@ func MintToken(to string, value *big.Int) error {
 }
 
 // 查看以太币余额
-func BalanceETH(from string) (*big.Int, error) {
+func (ch *Chain) BalanceETH(from string) (*big.Int, error) {
 	addr := common.HexToAddress(from)
-	value, err := ethcli.BalanceAt(context.Background(), addr, nil)
+	value, err := ch.cli.BalanceAt(context.Background(), addr, nil)
 	if err != nil {
 		log.Panic("Failed to BalanceAt ", err, from)
 		return nil, err
@@ -300,12 +517,12 @@ func BalanceETH(from string) (*big.Int, error) {
 }
 
 // 以太币coin转账 - 使用*big.Int避免int64数值溢出
-func Transfer(from, pass, toaddr string, value *big.Int) error {
+func (ch *Chain) Transfer(from, pass, toaddr string, value *big.Int) error {
 	//1. 钱包加载
 	w, _ := hdwallet.LoadWalletByPass(from, "./data", pass)
 	//2. 获取nonce
-	nonce, _ := ethcli.NonceAt(context.Background(), common.HexToAddress(from), nil)
-	chainId, err := ethcli.ChainID(context.Background())
+	nonce, _ := ch.cli.NonceAt(context.Background(), common.HexToAddress(from), nil)
+	chainId, err := ch.cli.ChainID(context.Background())
 	if err != nil {
 		log.Panic("Failed to get chainId", err)
 	}
@@ -321,208 +538,149 @@ func Transfer(from, pass, toaddr string, value *big.Int) error {
 		log.Panic("Failed to SignTx", err)
 	}
 	//5. 发送交易
-	return ethcli.SendTransaction(context.Background(), stx)
+	return ch.cli.SendTransaction(context.Background(), stx)
 }
 
-// token余额查询
-func Tokenbalance(from string) (int64, error) {
+// token余额查询，返回链上最小单位
+func (ch *Chain) Tokenbalance(from string) (*big.Int, error) {
 	// 构建CallOpts
 	fromaddr := common.HexToAddress(from)
 	opts := bind.CallOpts{
 		From: fromaddr,
 	}
 
-	value, err := instancePXC.BalanceOf(&opts, fromaddr)
-	if err != nil {
-		log.Panic("failed totoken.BalanceOf ", err)
-	}
-	fmt.Printf("%s's token balance is: %d\n", from, value.Int64())
-	return value.Int64(), err
-}
-
-func Tokendetail(who string) error {
-	// 1. 合约地址处理，这两个变量后面会使用
-	cAddress := common.HexToAddress(PXC_ADDR)
-	// 2. 计算需要监听的多个事件的哈希值
-	topicHashTransfer := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
-	topicHashApproval := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
-
-	// 3. 设置过滤条件，在Topics中添加多个事件哈希值
-	// 多个事件哈希放在同一个数组中表示逻辑或(OR)关系
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{cAddress},
-		Topics:    [][]common.Hash{{topicHashTransfer, topicHashApproval}},
-	}
-	// 3. 查询全部日志
-	logs, err := ethcli.FilterLogs(context.Background(), query)
-	if err != nil {
-		log.Panic("failed to FilterLogs", err)
-	}
-
-	for _, v := range logs {
-		// 检查日志中是否包含事件哈希主题
-		if len(v.Topics) > 0 {
-			// 处理Transfer事件
-			if v.Topics[0] == topicHashTransfer && len(v.Topics) == 3 {
-				fromF := v.Topics[1].Bytes()[len(v.Topics[1].Bytes())-20:]
-				to := v.Topics[2].Bytes()[len(v.Topics[2].Bytes())-20:]
-				val := big.NewInt(0)
-				val.SetBytes(v.Data)
-				fromAddr := fmt.Sprintf("0x%x", fromF)
-				toAddr := fmt.Sprintf("0x%x", to)
-				if strings.ToUpper(fromAddr) == strings.ToUpper(who) || strings.ToUpper(toAddr) == strings.ToUpper(who) {
-					direction := '-'
-					if strings.ToUpper(toAddr) == strings.ToUpper(who) {
-						direction = '+'
-					}
-					fmt.Printf("[Block %d] Transfer: %s -> %s, value: %c%d\n",
-						v.BlockNumber, fromAddr, toAddr, direction, val.Int64())
-				}
-			} else if v.Topics[0] == topicHashApproval && len(v.Topics) == 3 {
-				// 处理Approval事件
-				owner := v.Topics[1].Bytes()[len(v.Topics[1].Bytes())-20:]
-				approved := v.Topics[2].Bytes()[len(v.Topics[2].Bytes())-20:]
-				tokenId := big.NewInt(0)
-				tokenId.SetBytes(v.Data)
-				ownerAddr := fmt.Sprintf("0x%x", owner)
-				if strings.ToUpper(ownerAddr) == strings.ToUpper(who) {
-					fmt.Printf("[Block %d] Approval: owner=%s, approved=%s, tokenId=%d\n",
-						v.BlockNumber, ownerAddr, fmt.Sprintf("0x%x", approved), tokenId.Int64())
-				}
-			}
-		}
+	value, err := ch.pxc.BalanceOf(&opts, fromaddr)
+	if err != nil {
+		fmt.Println("failed to token.BalanceOf ", err)
+		return nil, err
 	}
-	return nil
+	fmt.Printf("%s's token balance is: %d\n", from, value)
+	return value, nil
+}
+
+// Token交易明细条目
+type TokenHistory struct {
+	Event        string `json:"event"`              //事件名称
+	Direction    string `json:"direction"`          //方向：in转入/被授权，out转出/授权他人，self自己转给自己
+	Counterparty string `json:"counterparty"`       //交易对手地址
+	Amount       string `json:"amount,omitempty"`   //PXC金额（按精度换算后的十进制字符串）
+	TokenID      string `json:"token_id,omitempty"` //PXA tokenId
+	Approved     *bool  `json:"approved,omitempty"` //ApprovalForAll的授权状态
+	BlockNumber  uint64 `json:"block_number"`       //区块高度
+	TxHash       string `json:"tx_hash"`            //交易哈希
+	Status       string `json:"status"`             //确认状态 pending/confirmed
+}
+
+// 历史查询条件
+type HistoryQuery struct {
+	Event     string //事件名称，为空表示全部
+	FromBlock uint64 //起始区块，0表示不限
+	ToBlock   uint64 //结束区块，0表示不限
+}
+
+// PXC20支持查询的事件
+var PXC20Events = []string{"Transfer", "Approval"}
+
+// PXA721支持查询的事件
+var PXA721Events = []string{"Transfer", "Approval", "ApprovalForAll"}
+
+// token交易明细查询（分页），数据来源于事件索引
+func (ch *Chain) Tokendetail(who string, q HistoryQuery, pageNum, pageSize int) (*utils.PageResult[TokenHistory], error) {
+	return ch.tokenHistory(ch.pxcAddr, who, q, pageNum, pageSize)
+}
+
+// PXA721交易明细查询（分页），数据来源于事件索引
+func (ch *Chain) PXA721detail(who string, q HistoryQuery, pageNum, pageSize int) (*utils.PageResult[TokenHistory], error) {
+	return ch.tokenHistory(ch.pxaAddr, who, q, pageNum, pageSize)
+}
+
+func (ch *Chain) tokenHistory(contract, who string, q HistoryQuery, pageNum, pageSize int) (*utils.PageResult[TokenHistory], error) {
+	whoAddr := common.HexToAddress(who)
+	page, err := dbs.QueryChainEvents(dbs.ChainEventQuery{
+		ChainID:   ch.ChainID(),
+		Contract:  common.HexToAddress(contract).Hex(),
+		Address:   whoAddr.Hex(),
+		Event:     q.Event,
+		FromBlock: q.FromBlock,
+		ToBlock:   q.ToBlock,
+	}, pageNum, pageSize)
+	if err != nil {
+		fmt.Println("failed to QueryChainEvents", err)
+		return nil, err
+	}
+	rows := make([]TokenHistory, 0, len(page.Rows))
+	for _, e := range page.Rows {
+		rows = append(rows, ch.toHistory(whoAddr, e))
+	}
+	return &utils.PageResult[TokenHistory]{
+		Rows:     rows,
+		Total:    page.Total,
+		PageSize: page.PageSize,
+		PageNum:  page.PageNum,
+	}, nil
+}
+
+// 将事件转换为某地址视角的明细条目
+func (ch *Chain) toHistory(who common.Address, e dbs.ChainEvent) TokenHistory {
+	h := TokenHistory{
+		Event:       e.Event,
+		BlockNumber: e.BlockNumber,
+		TxHash:      e.TxHash,
+		Status:      e.Status,
+	}
+	// 判断方向和交易对手
+	from := common.HexToAddress(e.From)
+	to := common.HexToAddress(e.To)
+	switch {
+	case from == who && to == who:
+		h.Direction = "self"
+		h.Counterparty = to.Hex()
+	case to == who:
+		h.Direction = "in"
+		h.Counterparty = from.Hex()
+	default:
+		h.Direction = "out"
+		h.Counterparty = to.Hex()
+	}
+	// PXC事件值为金额，PXA事件值为tokenId
+	if e.Event == "ApprovalForAll" {
+		approved := e.Approved
+		h.Approved = &approved
+	} else if common.HexToAddress(e.Contract) == common.HexToAddress(ch.pxcAddr) {
+		h.Amount = ch.formatPXC(e.Value)
+	} else {
+		h.TokenID = e.Value
+	}
+	return h
 }
 
-func PXA721detail(who string) error {
-	// 1. 合约地址处理
-	cAddress := common.HexToAddress(PXA_ADDR)
-	// 2. 计算需要监听的多个事件的哈希值
-	topicHashTransfer := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
-	topicHashApproval := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
-	topicHashApprovalForAll := crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)"))
-
-	// 3. 设置过滤条件，在Topics中添加多个事件哈希值
-	// 多个事件哈希放在同一个数组中表示逻辑或(OR)关系
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{cAddress},
-		Topics:    [][]common.Hash{{topicHashTransfer, topicHashApproval, topicHashApprovalForAll}},
-	}
-	// 3. 查询全部日志
-	logs, err := ethcli.FilterLogs(context.Background(), query)
-	if err != nil {
-		log.Panic("failed to FilterLogs", err)
-	}
-
-	for _, v := range logs {
-		// 检查 Transfer 事件
-		if v.Topics[0] == topicHashTransfer && len(v.Topics) == 4 {
-			// 正确提取 from 地址（前24个字节是补零，后20个字节是地址）
-			if len(v.Topics[1].Bytes()) < 20 {
-				fmt.Printf("[警告] Transfer事件的from地址数据不完整\n")
-				continue
-			}
-			fromBytes := v.Topics[1].Bytes()[len(v.Topics[1].Bytes())-20:]
-			fromAddr := fmt.Sprintf("0x%x", fromBytes)
-
-			// 正确提取 to 地址
-			if len(v.Topics[2].Bytes()) < 20 {
-				fmt.Printf("[警告] Transfer事件的to地址数据不完整\n")
-				continue
-			}
-			toBytes := v.Topics[2].Bytes()[len(v.Topics[2].Bytes())-20:]
-			toAddr := fmt.Sprintf("0x%x", toBytes)
-
-			// 正确提取 tokenId - tokenId 是 indexed 参数，应该在 Topics[3] 中
-			if len(v.Topics) < 4 {
-				fmt.Printf("[警告] Transfer事件缺少tokenId主题\n")
-				continue
-			}
-			tokenId := big.NewInt(0)
-			tokenId.SetBytes(v.Topics[3].Bytes()) // 从 Topics[3] 提取 tokenId
-
-			// 转换地址为大写进行比较
-			fromAddrUpper := strings.ToUpper(fromAddr)
-			toAddrUpper := strings.ToUpper(toAddr)
-			whoUpper := strings.ToUpper(who)
-
-			// 检查地址匹配
-			if fromAddrUpper == whoUpper || toAddrUpper == whoUpper {
-				fmt.Printf("[Block %d] Transfer 事件: 发送方=%s, 接收方=%s, tokenId=%d\n",
-					v.BlockNumber, fromAddr, toAddr, tokenId.Int64())
-			}
-
-			// 检查 Approval 事件
-		} else if v.Topics[0] == topicHashApproval && len(v.Topics) == 4 {
-			// 正确提取 owner 地址
-			if len(v.Topics[1].Bytes()) < 20 {
-				fmt.Printf("[警告] Approval事件的owner地址数据不完整\n")
-				continue
-			}
-			ownerBytes := v.Topics[1].Bytes()[len(v.Topics[1].Bytes())-20:]
-			ownerAddr := fmt.Sprintf("0x%x", ownerBytes)
-
-			// 正确提取 approved 地址
-			if len(v.Topics[2].Bytes()) < 20 {
-				fmt.Printf("[警告] Approval事件的approved地址数据不完整\n")
-				continue
-			}
-			approvedBytes := v.Topics[2].Bytes()[len(v.Topics[2].Bytes())-20:]
-			approvedAddr := fmt.Sprintf("0x%x", approvedBytes)
-
-			// 正确提取 tokenId - tokenId 是 indexed 参数，应该在 Topics[3] 中
-			if len(v.Topics) < 4 {
-				fmt.Printf("[警告] Approval事件缺少tokenId主题\n")
-				continue
-			}
-			tokenId := big.NewInt(0)
-			tokenId.SetBytes(v.Topics[3].Bytes()) // 从 Topics[3] 提取 tokenId
-
-			// 转换地址为大写进行比较
-			ownerAddrUpper := strings.ToUpper(ownerAddr)
-			whoUpper := strings.ToUpper(who)
-
-			// 检查地址匹配
-			if ownerAddrUpper == whoUpper {
-				fmt.Printf("[Block %d] Approval 事件: 所有者=%s, 授权方=%s, tokenId=%d\n",
-					v.BlockNumber, ownerAddr, approvedAddr, tokenId.Int64())
-			}
-
-			// 检查 ApprovalForAll 事件
-		} else if v.Topics[0] == topicHashApprovalForAll && len(v.Topics) == 3 {
-			// 正确提取 owner 地址
-			if len(v.Topics[1].Bytes()) < 20 {
-				fmt.Printf("[警告] ApprovalForAll事件的owner地址数据不完整\n")
-				continue
-			}
-			ownerBytes := v.Topics[1].Bytes()[len(v.Topics[1].Bytes())-20:]
-			ownerAddr := fmt.Sprintf("0x%x", ownerBytes)
-
-			// 正确提取 operator 地址
-			if len(v.Topics[2].Bytes()) < 20 {
-				fmt.Printf("[警告] ApprovalForAll事件的operator地址数据不完整\n")
-				continue
-			}
-			operatorBytes := v.Topics[2].Bytes()[len(v.Topics[2].Bytes())-20:]
-			operatorAddr := fmt.Sprintf("0x%x", operatorBytes)
-
-			// 正确提取 approved 布尔值
-			approved := false
-			if len(v.Data) > 0 {
-				approved = v.Data[len(v.Data)-1] == 1
-			}
-
-			// 转换地址为大写进行比较
-			ownerAddrUpper := strings.ToUpper(ownerAddr)
-			whoUpper := strings.ToUpper(who)
-
-			// 检查地址匹配
-			if ownerAddrUpper == whoUpper {
-				fmt.Printf("[Block %d] ApprovalForAll 事件: 所有者=%s, 操作员=%s, 是否授权=%v\n",
-					v.BlockNumber, ownerAddr, operatorAddr, approved)
-			}
+// operator授权状态
+type Operator struct {
+	Operator string `json:"operator"` //被授权地址
+	Approved bool   `json:"approved"` //是否已批量授权
+	Market   bool   `json:"market"`   //是否为平台地址
+}
+
+// 查询owner的operator授权状态：候选地址为平台地址和事件索引中授权过的地址，以链上状态为准
+func (ch *Chain) QueryOperators(owner string) ([]Operator, error) {
+	candidates, err := dbs.QueryOperators(ch.ChainID(), common.HexToAddress(ch.pxaAddr).Hex(), common.HexToAddress(owner).Hex())
+	if err != nil {
+		return nil, err
+	}
+	market := common.HexToAddress(ch.adminAddr)
+	seen := map[common.Address]bool{}
+	result := []Operator{}
+	for _, c := range append([]string{market.Hex()}, candidates...) {
+		addr := common.HexToAddress(c)
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		approved, err := ch.IsApprovedForAll(owner, addr.Hex())
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, Operator{Operator: addr.Hex(), Approved: approved, Market: addr == market})
 	}
-	return nil
+	return result, nil
 }
@@ -0,0 +1,242 @@
+package eths
+
+import (
+	"context"
+	"copyright/dbs"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 索引器追上最新区块后的轮询间隔
+const INDEX_INTERVAL = 5 * time.Second
+
+// 每轮最多扫描的区块数，避免节点单次返回过多日志
+const INDEX_BATCH = 2000
+
+// 需要索引的合约及其事件扫描函数
+type indexedContract struct {
+	address string
+	scan    func(opts *bind.FilterOpts) ([]dbs.ChainEvent, error)
+}
+
+func (ch *Chain) indexedContracts() []indexedContract {
+	return []indexedContract{
+		{common.HexToAddress(ch.pxcAddr).Hex(), ch.scanPXC20},
+		{common.HexToAddress(ch.pxaAddr).Hex(), ch.scanPXA721},
+	}
+}
+
+// 为每条已连接的链在后台启动事件索引器
+func RunIndexer() {
+	for _, ch := range chainList {
+		go ch.runIndexer()
+	}
+}
+
+// 链上事件索引器：先从检查点回填历史区块，追上后持续跟踪新区块，
+// 并重新扫描未达到确认深度的区块以应对链重组
+func (ch *Chain) runIndexer() {
+	for {
+		caughtUp, err := ch.indexOnce()
+		if err != nil {
+			fmt.Println("failed to index chain events of chain", ch.ChainID(), err)
+		}
+		if caughtUp || err != nil {
+			time.Sleep(INDEX_INTERVAL)
+		}
+	}
+}
+
+// 对每个合约扫描一批区块，返回是否所有合约都已追上最新区块
+func (ch *Chain) indexOnce() (bool, error) {
+	latest, err := ch.cli.BlockNumber(context.Background())
+	if err != nil {
+		fmt.Println("failed to get latest block number", err)
+		return false, err
+	}
+	caughtUp := true
+	for _, c := range ch.indexedContracts() {
+		//1. 读取检查点
+		checkpoint, found, err := dbs.QueryCheckpoint(ch.ChainID(), c.address)
+		if err != nil {
+			return false, err
+		}
+		// 尚未索引时从合约部署区块开始
+		start := ch.deployBlock
+		if found {
+			start = rescanStart(checkpoint)
+		}
+		if start > latest {
+			// 没有新区块，仅更新确认状态
+			if err = ch.confirmEvents(c.address, latest); err != nil {
+				return false, err
+			}
+			continue
+		}
+		end := start + INDEX_BATCH - 1
+		if end >= latest {
+			end = latest
+		} else {
+			caughtUp = false
+		}
+		//2. 扫描区块范围内的事件
+		events, err := c.scan(&bind.FilterOpts{Start: start, End: &end})
+		if err != nil {
+			return false, err
+		}
+		//3. 保存事件并推进检查点
+		err = dbs.SaveChainEvents(ch.ChainID(), c.address, events, end)
+		if err != nil {
+			return false, err
+		}
+		if len(events) > 0 {
+			fmt.Printf("indexed %d events of %s in blocks [%d, %d]\n", len(events), c.address, start, end)
+		}
+		//4. 回滚被重组的事件并确认达到深度的事件
+		if err = ch.confirmEvents(c.address, latest); err != nil {
+			return false, err
+		}
+	}
+	//5. 跟踪拍卖交割交易的确认状态
+	if err = ch.confirmTrades(latest); err != nil {
+		return false, err
+	}
+	return caughtUp, nil
+}
+
+// 根据原始日志构造事件记录
+func newChainEvent(event string, raw types.Log) dbs.ChainEvent {
+	return dbs.ChainEvent{
+		Contract:    raw.Address.Hex(),
+		Event:       event,
+		BlockNumber: raw.BlockNumber,
+		BlockHash:   raw.BlockHash.Hex(),
+		TxHash:      raw.TxHash.Hex(),
+		LogIndex:    raw.Index,
+		Status:      dbs.STATUS_PENDING,
+	}
+}
+
+func pxc20TransferEvent(ev *Pxc20Transfer) dbs.ChainEvent {
+	e := newChainEvent("Transfer", ev.Raw)
+	e.From = ev.From.Hex()
+	e.To = ev.To.Hex()
+	e.Value = ev.Value.String()
+	return e
+}
+
+func pxc20ApprovalEvent(ev *Pxc20Approval) dbs.ChainEvent {
+	e := newChainEvent("Approval", ev.Raw)
+	e.From = ev.Owner.Hex()
+	e.To = ev.Spender.Hex()
+	e.Value = ev.Value.String()
+	return e
+}
+
+func pxa721TransferEvent(ev *Pxa721Transfer) dbs.ChainEvent {
+	e := newChainEvent("Transfer", ev.Raw)
+	e.From = ev.From.Hex()
+	e.To = ev.To.Hex()
+	e.Value = ev.TokenId.String()
+	return e
+}
+
+func pxa721ApprovalEvent(ev *Pxa721Approval) dbs.ChainEvent {
+	e := newChainEvent("Approval", ev.Raw)
+	e.From = ev.Owner.Hex()
+	e.To = ev.Approved.Hex()
+	e.Value = ev.TokenId.String()
+	return e
+}
+
+func pxa721ApprovalForAllEvent(ev *Pxa721ApprovalForAll) dbs.ChainEvent {
+	e := newChainEvent("ApprovalForAll", ev.Raw)
+	e.From = ev.Owner.Hex()
+	e.To = ev.Operator.Hex()
+	e.Approved = ev.Approved
+	return e
+}
+
+// 扫描PXC20合约的Transfer和Approval事件
+func (ch *Chain) scanPXC20(opts *bind.FilterOpts) ([]dbs.ChainEvent, error) {
+	events := []dbs.ChainEvent{}
+	transfers, err := ch.pxc.FilterTransfer(opts, nil, nil)
+	if err != nil {
+		fmt.Println("failed to FilterTransfer of PXC20", err)
+		return nil, err
+	}
+	defer transfers.Close()
+	for transfers.Next() {
+		events = append(events, pxc20TransferEvent(transfers.Event))
+	}
+	if err = transfers.Error(); err != nil {
+		fmt.Println("failed to iterate Transfer of PXC20", err)
+		return nil, err
+	}
+
+	approvals, err := ch.pxc.FilterApproval(opts, nil, nil)
+	if err != nil {
+		fmt.Println("failed to FilterApproval of PXC20", err)
+		return nil, err
+	}
+	defer approvals.Close()
+	for approvals.Next() {
+		events = append(events, pxc20ApprovalEvent(approvals.Event))
+	}
+	if err = approvals.Error(); err != nil {
+		fmt.Println("failed to iterate Approval of PXC20", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// 扫描PXA721合约的Transfer、Approval和ApprovalForAll事件
+func (ch *Chain) scanPXA721(opts *bind.FilterOpts) ([]dbs.ChainEvent, error) {
+	events := []dbs.ChainEvent{}
+	transfers, err := ch.pxa.FilterTransfer(opts, nil, nil, nil)
+	if err != nil {
+		fmt.Println("failed to FilterTransfer of PXA721", err)
+		return nil, err
+	}
+	defer transfers.Close()
+	for transfers.Next() {
+		events = append(events, pxa721TransferEvent(transfers.Event))
+	}
+	if err = transfers.Error(); err != nil {
+		fmt.Println("failed to iterate Transfer of PXA721", err)
+		return nil, err
+	}
+
+	approvals, err := ch.pxa.FilterApproval(opts, nil, nil, nil)
+	if err != nil {
+		fmt.Println("failed to FilterApproval of PXA721", err)
+		return nil, err
+	}
+	defer approvals.Close()
+	for approvals.Next() {
+		events = append(events, pxa721ApprovalEvent(approvals.Event))
+	}
+	if err = approvals.Error(); err != nil {
+		fmt.Println("failed to iterate Approval of PXA721", err)
+		return nil, err
+	}
+
+	operators, err := ch.pxa.FilterApprovalForAll(opts, nil, nil)
+	if err != nil {
+		fmt.Println("failed to FilterApprovalForAll of PXA721", err)
+		return nil, err
+	}
+	defer operators.Close()
+	for operators.Next() {
+		events = append(events, pxa721ApprovalForAllEvent(operators.Event))
+	}
+	if err = operators.Error(); err != nil {
+		fmt.Println("failed to iterate ApprovalForAll of PXA721", err)
+		return nil, err
+	}
+	return events, nil
+}
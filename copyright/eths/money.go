@@ -0,0 +1,95 @@
+package eths
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// 代币金额单位：合约的精度和符号，用于在人类可读的十进制字符串与链上最小单位之间换算
+type Money struct {
+	Decimals uint8  //小数位数
+	Symbol   string //代币符号
+}
+
+// 获取本链PXC代币单位，首次使用时从合约读取，读取失败时不缓存，下次调用重新读取
+func (ch *Chain) PXC() (*Money, error) {
+	ch.pxcMoneyMu.Lock()
+	defer ch.pxcMoneyMu.Unlock()
+	if ch.pxcMoney != nil {
+		return ch.pxcMoney, nil
+	}
+	decimals, err := ch.pxc.Decimals(&bind.CallOpts{})
+	if err != nil {
+		fmt.Println("failed to get PXC decimals", err)
+		return nil, err
+	}
+	symbol, err := ch.pxc.Symbol(&bind.CallOpts{})
+	if err != nil {
+		fmt.Println("failed to get PXC symbol", err)
+		return nil, err
+	}
+	ch.pxcMoney = &Money{Decimals: decimals, Symbol: symbol}
+	return ch.pxcMoney, nil
+}
+
+// 10^Decimals，即一个完整代币对应的最小单位数量
+func (m *Money) unit() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(m.Decimals)), nil)
+}
+
+// 将十进制字符串（如"1.5"）转换为最小单位，不允许负数和超出精度的小数位
+func (m *Money) Parse(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || hasDot && fracPart == "" {
+		return nil, errors.New("invalid amount format")
+	}
+	if len(fracPart) > int(m.Decimals) {
+		return nil, fmt.Errorf("amount has more than %d decimal places", m.Decimals)
+	}
+	digits := intPart + fracPart + strings.Repeat("0", int(m.Decimals)-len(fracPart))
+	if strings.Trim(digits, "0123456789") != "" {
+		return nil, errors.New("invalid amount format")
+	}
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, errors.New("invalid amount format")
+	}
+	return value, nil
+}
+
+// 将最小单位转换为十进制字符串，去掉末尾多余的0
+func (m *Money) Format(value *big.Int) string {
+	if value == nil {
+		return "0"
+	}
+	abs := new(big.Int).Abs(value)
+	intPart, fracPart := new(big.Int).QuoRem(abs, m.unit(), new(big.Int))
+	s := intPart.String()
+	if fracPart.Sign() > 0 {
+		frac := fracPart.String()
+		frac = strings.Repeat("0", int(m.Decimals)-len(frac)) + frac
+		s += "." + strings.TrimRight(frac, "0")
+	}
+	if value.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// 将链上最小单位的十进制字符串格式化为PXC金额，代币单位读取失败时原样返回
+func (ch *Chain) formatPXC(value string) string {
+	m, err := ch.PXC()
+	if err != nil {
+		return value
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return value
+	}
+	return m.Format(v)
+}
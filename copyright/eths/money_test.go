@@ -0,0 +1,97 @@
+package eths
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMoneyParse(t *testing.T) {
+	pxc := &Money{Decimals: 18, Symbol: "PXC"}
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"1", "1000000000000000000"},
+		{"1.5", "1500000000000000000"},
+		{" 2.25 ", "2250000000000000000"},
+		{".5", "500000000000000000"},
+		{"0.000000000000000001", "1"},
+		{"007", "7000000000000000000"},
+		{"123456789012345678901234567890", "123456789012345678901234567890000000000000000000"},
+	}
+	for _, c := range cases {
+		got, err := pxc.Parse(c.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", c.in, err)
+			continue
+		}
+		if got.String() != c.want {
+			t.Errorf("Parse(%q) = %s, want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestMoneyParseInvalid(t *testing.T) {
+	pxc := &Money{Decimals: 18, Symbol: "PXC"}
+	for _, in := range []string{
+		"", ".", "1.", "-1", "+1", "1e18", "0x10", "1.2.3", "1,5", "abc",
+		"0.0000000000000000001", // 超出18位精度
+	} {
+		if got, err := pxc.Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, want error", in, got)
+		}
+	}
+	// 精度为0的代币不接受小数
+	whole := &Money{Decimals: 0}
+	if got, err := whole.Parse("1.5"); err == nil {
+		t.Errorf("Parse(1.5) with 0 decimals = %s, want error", got)
+	}
+	if got, err := whole.Parse("15"); err != nil || got.Int64() != 15 {
+		t.Errorf("Parse(15) with 0 decimals = %v, %v", got, err)
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	pxc := &Money{Decimals: 18, Symbol: "PXC"}
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"1", "0.000000000000000001"},
+		{"1000000000000000000", "1"},
+		{"1500000000000000000", "1.5"},
+		{"1050000000000000000", "1.05"},
+		{"-2500000000000000000", "-2.5"},
+		{"123456789012345678901234567890000000000000000000", "123456789012345678901234567890"},
+	}
+	for _, c := range cases {
+		v, _ := new(big.Int).SetString(c.in, 10)
+		if got := pxc.Format(v); got != c.want {
+			t.Errorf("Format(%s) = %s, want %s", c.in, got, c.want)
+		}
+	}
+	if got := pxc.Format(nil); got != "0" {
+		t.Errorf("Format(nil) = %s, want 0", got)
+	}
+	if got := (&Money{Decimals: 6}).Format(big.NewInt(1234567)); got != "1.234567" {
+		t.Errorf("Format with 6 decimals = %s, want 1.234567", got)
+	}
+}
+
+func TestMoneyRoundTrip(t *testing.T) {
+	for _, decimals := range []uint8{0, 2, 6, 18} {
+		m := &Money{Decimals: decimals}
+		for _, s := range []string{"0", "1", "10", "3.14", "0.01", "1000000.5"} {
+			v, err := m.Parse(s)
+			if err != nil {
+				// 小数位数超过精度的取值不参与往返校验
+				continue
+			}
+			if got := m.Format(v); got != s {
+				t.Errorf("decimals %d: Format(Parse(%q)) = %q", decimals, s, got)
+			}
+		}
+	}
+}
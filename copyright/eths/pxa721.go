@@ -31,13 +31,35 @@ var (
 
 // Pxa721MetaData contains all meta data concerning the Pxa721 contract.
 var Pxa721MetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"approved\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"getApproved\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"orgTokenID\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"getSplitToken\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"}],\"name\":\"isApprovedForAll\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"ownerOf\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"orgtokenId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"}],\"name\":\"partTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"setApprovalForAll\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceId\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenID\",\"type\":\"uint256\"}],\"name\":\"uploadMint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_name\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"approved\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"ApprovalForAll\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"_tokenPrice\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"_tokenSplitAsset\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"orgTokenID\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"getApproved\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"orgTokenID\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"getSplitToken\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"}],\"name\":\"isApprovedForAll\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"ownerOf\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"orgtokenId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"}],\"name\":\"partTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"bytes\",\"name\":\"_data\",\"type\":\"bytes\"}],\"name\":\"safeTransferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"operator\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"approved\",\"type\":\"bool\"}],\"name\":\"setApprovalForAll\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceId\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"}],\"name\":\"uploadMint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561001057600080fd5b50604051612a2d380380612a2d833981810160405281019061003291906101bd565b80600090816100419190610427565b50506104f9565b6000604051905090565b600080fd5b600080fd5b600080fd5b600080fd5b6000601f19601f8301169050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b6100af82610066565b810181811067ffffffffffffffff821117156100ce576100cd610077565b5b80604052505050565b60006100e1610048565b90506100ed82826100a6565b919050565b600067ffffffffffffffff82111561010d5761010c610077565b5b61011682610066565b9050602081019050919050565b60005b83811015610141578082015181840152602081019050610126565b60008484015250505050565b600061016061015b846100f2565b6100d7565b90508281526020810184848401111561017c5761017b610061565b5b610187848285610123565b509392505050565b600082601f8301126101a4576101a361005c565b5b81516101b484826020860161014d565b91505092915050565b6000602082840312156101d3576101d2610052565b5b600082015167ffffffffffffffff8111156101f1576101f0610057565b5b6101fd8482850161018f565b91505092915050565b600081519050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b6000600282049050600182168061025857607f821691505b60208210810361026b5761026a610211565b5b50919050565b60008190508160005260206000209050919050565b60006020601f8301049050919050565b600082821b905092915050565b6000600883026102d37fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff82610296565b6102dd8683610296565b95508019841693508086168417925050509392505050565b6000819050919050565b6000819050919050565b600061032461031f61031a846102f5565b6102ff565b6102f5565b9050919050565b6000819050919050565b61033e83610309565b61035261034a8261032b565b8484546102a3565b825550505050565b600090565b61036761035a565b610372818484610335565b505050565b5b818110156103965761038b60008261035f565b600181019050610378565b5050565b601f8211156103db576103ac81610271565b6103b584610286565b810160208510156103c4578190505b6103d86103d085610286565b830182610377565b50505b505050565b600082821c905092915050565b60006103fe600019846008026103e0565b1980831691505092915050565b600061041783836103ed565b9150826002028217905092915050565b61043082610206565b67ffffffffffffffff81111561044957610448610077565b5b6104538254610240565b61045e82828561039a565b600060209050601f831160018114610491576000841561047f578287015190505b610489858261040b565b8655506104f1565b601f19841661049f86610271565b60005b828110156104c7578489015182556001820191506020850194506020810190506104a2565b868310156104e457848901516104e0601f8916826103ed565b8355505b6001600288020188555050505b505050505050565b612525806105086000396000f3fe608060405234801561001057600080fd5b50600436106101005760003560e01c80636866d14411610097578063b88d4fde11610066578063b88d4fde146102d0578063c8e79b20146102ec578063d86af76314610308578063e985e9c51461033857610100565b80636866d1441461023857806370a0823114610268578063a22cb46514610298578063a2dc66de146102b457610100565b806323b872dd116100d357806323b872dd1461019f5780632b580117146101bb57806342842e0e146101ec5780636352211e1461020857610100565b806301ffc9a71461010557806306fdde0314610135578063081812fc14610153578063095ea7b314610183575b600080fd5b61011f600480360381019061011a91906116b9565b610368565b60405161012c9190611701565b60405180910390f35b61013d6103ca565b60405161014a91906117ac565b60405180910390f35b61016d60048036038101906101689190611804565b610458565b60405161017a9190611872565b60405180910390f35b61019d600480360381019061019891906118b9565b6104dd565b005b6101b960048036038101906101b491906118f9565b6104eb565b005b6101d560048036038101906101d09190611804565b610544565b6040516101e392919061195b565b60405180910390f35b610206600480360381019061020191906118f9565b610568565b005b610222600480360381019061021d9190611804565b610588565b60405161022f9190611872565b60405180910390f35b610252600480360381019061024d9190611804565b610639565b60405161025f9190611984565b60405180910390f35b610282600480360381019061027d919061199f565b610651565b60405161028f9190611984565b60405180910390f35b6102b260048036038101906102ad91906119f8565b610708565b005b6102ce60048036038101906102c991906118b9565b610873565b005b6102ea60048036038101906102e59190611b6d565b610afd565b005b61030660048036038101906103019190611bf0565b610b58565b005b610322600480360381019061031d9190611c6b565b610f99565b60405161032f9190611984565b60405180910390f35b610352600480360381019061034d9190611cab565b610fcf565b60405161035f9190611701565b60405180910390f35b60006380ac58cd60e01b827bffffffffffffffffffffffffffffffffffffffffffffffffffffffff191614806103c357506301ffc9a760e01b827bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916145b9050919050565b600080546103d790611d1a565b80601f016020809104026020016040519081016040528092919081815260200182805461040390611d1a565b80156104505780601f1061042557610100808354040283529160200191610450565b820191906000526020600020905b81548152906001019060200180831161043357829003601f168201915b505050505081565b600061046382611063565b6104a2576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161049990611dbd565b60405180910390fd5b6002600083815260200190815260200160002060009054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050919050565b6104e782826110cf565b5050565b6104f53382611188565b610534576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161052b90611e4f565b60405180910390fd5b61053f83838361121d565b505050565b60066020528060005260406000206000915090508060000154908060010154905082565b6105838383836040518060200160405280600081525061146d565b505050565b6000806001600084815260200190815260200160002060009054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050600073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1603610630576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161062790611ee1565b60405180910390fd5b80915050919050565b60056020528060005260406000206000915090505481565b60008073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff16036106c1576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016106b890611f73565b60405180910390fd5b600360008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020549050919050565b3373ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff1603610776576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161076d90611fdf565b60405180910390fd5b80600460003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060006101000a81548160ff0219169083151502179055508173ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167f17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31836040516108679190611701565b60405180910390a35050565b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff16036108e2576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016108d99061204b565b60405180910390fd5b6108eb81611063565b1561092b576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610922906120b7565b60405180910390fd5b816001600083815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055506001600360008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008282546109cd9190612106565b92505081905550808273ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a460008183604051602001610a4592919061213a565b6040516020818303038152906040528051906020012060001c90506000604051806040016040528060648152602001848152509050836001600084815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508060066000848152602001908152602001600020600082015181600001556020820151816001015590505050505050565b610b073383611188565b610b46576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610b3d90611e4f565b60405180910390fd5b610b528484848461146d565b50505050565b60008385604051602001610b6d92919061213a565b6040516020818303038152906040528051906020012060001c905060008487604051602001610b9d92919061213a565b6040516020818303038152906040528051906020012060001c905060648411158015610bc95750600084115b610c08576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610bff906121af565b60405180910390fd5b8360066000838152602001908152602001600020600001541015610c2b57600080fd5b83600660008481526020019081526020016000206000016000828254610c519190612106565b9250508190555084600660008481526020019081526020016000206001018190555083600660008381526020019081526020016000206000016000828254610c9991906121cf565b92505081905550600060066000838152602001908152602001600020600001541115610d1657866001600083815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550610dc6565b60006001600083815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555080600073ffffffffffffffffffffffffffffffffffffffff168873ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a45b856001600084815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550818673ffffffffffffffffffffffffffffffffffffffff168873ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a46064600660008481526020019081526020016000206000015403610f785760006001600087815260200190815260200160002060009054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050866001600088815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550858773ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a4505b82600560008781526020019081526020016000208190555050505050505050565b60008282604051602001610fae92919061213a565b6040516020818303038152906040528051906020012060001c905092915050565b6000600460008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060009054906101000a900460ff16905092915050565b60008073ffffffffffffffffffffffffffffffffffffffff166001600084815260200190815260200160002060009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1614159050919050565b816002600083815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550808273ffffffffffffffffffffffffffffffffffffffff1661114283610588565b73ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b92560405160405180910390a45050565b60008061119483610588565b90508073ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff16148061120357508373ffffffffffffffffffffffffffffffffffffffff166111eb84610458565b73ffffffffffffffffffffffffffffffffffffffff16145b8061121457506112138185610fcf565b5b91505092915050565b8273ffffffffffffffffffffffffffffffffffffffff1661123d82610588565b73ffffffffffffffffffffffffffffffffffffffff1614611293576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161128a90612275565b60405180910390fd5b600073ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff1603611302576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016112f990612307565b60405180910390fd5b61130d6000826110cf565b6001600360008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020600082825461135d91906121cf565b925050819055506001600360008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008282546113b49190612106565b92505081905550816001600083815260200190815260200160002060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550808273ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60405160405180910390a4505050565b61147884848461121d565b611484848484846114c9565b6114c3576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016114ba90612399565b60405180910390fd5b50505050565b6000808473ffffffffffffffffffffffffffffffffffffffff163b036114f25760019050611645565b6000808573ffffffffffffffffffffffffffffffffffffffff1663150b7a0260e01b3389888860405160240161152b949392919061240e565b604051602081830303815290604052907bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19166020820180517bffffffffffffffffffffffffffffffffffffffffffffffffffffffff83818316178352505050506040516115959190612496565b6000604051808303816000865af19150503d80600081146115d2576040519150601f19603f3d011682016040523d82523d6000602084013e6115d7565b606091505b5091509150818015611640575063150b7a0260e01b7bffffffffffffffffffffffffffffffffffffffffffffffffffffffff19168180602001905181019061161f91906124c2565b7bffffffffffffffffffffffffffffffffffffffffffffffffffffffff1916145b925050505b949350505050565b6000604051905090565b600080fd5b600080fd5b60007fffffffff0000000000000000000000000000000000000000000000000000000082169050919050565b61169681611661565b81146116a157600080fd5b50565b6000813590506116b38161168d565b92915050565b6000602082840312156116cf576116ce611657565b5b60006116dd848285016116a4565b91505092915050565b60008115159050919050565b6116fb816116e6565b82525050565b600060208201905061171660008301846116f2565b92915050565b600081519050919050565b600082825260208201905092915050565b60005b8381101561175657808201518184015260208101905061173b565b60008484015250505050565b6000601f19601f8301169050919050565b600061177e8261171c565b6117888185611727565b9350611798818560208601611738565b6117a181611762565b840191505092915050565b600060208201905081810360008301526117c68184611773565b905092915050565b6000819050919050565b6117e1816117ce565b81146117ec57600080fd5b50565b6000813590506117fe816117d8565b92915050565b60006020828403121561181a57611819611657565b5b6000611828848285016117ef565b91505092915050565b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b600061185c82611831565b9050919050565b61186c81611851565b82525050565b60006020820190506118876000830184611863565b92915050565b61189681611851565b81146118a157600080fd5b50565b6000813590506118b38161188d565b92915050565b600080604083850312156118d0576118cf611657565b5b60006118de858286016118a4565b92505060206118ef858286016117ef565b9150509250929050565b60008060006060848603121561191257611911611657565b5b6000611920868287016118a4565b9350506020611931868287016118a4565b9250506040611942868287016117ef565b9150509250925092565b611955816117ce565b82525050565b6000604082019050611970600083018561194c565b61197d602083018461194c565b9392505050565b6000602082019050611999600083018461194c565b92915050565b6000602082840312156119b5576119b4611657565b5b60006119c3848285016118a4565b91505092915050565b6119d5816116e6565b81146119e057600080fd5b50565b6000813590506119f2816119cc565b92915050565b60008060408385031215611a0f57611a0e611657565b5b6000611a1d858286016118a4565b9250506020611a2e858286016119e3565b9150509250929050565b600080fd5b600080fd5b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b611a7a82611762565b810181811067ffffffffffffffff82111715611a9957611a98611a42565b5b80604052505050565b6000611aac61164d565b9050611ab88282611a71565b919050565b600067ffffffffffffffff821115611ad857611ad7611a42565b5b611ae182611762565b9050602081019050919050565b82818337600083830152505050565b6000611b10611b0b84611abd565b611aa2565b905082815260208101848484011115611b2c57611b2b611a3d565b5b611b37848285611aee565b509392505050565b600082601f830112611b5457611b53611a38565b5b8135611b64848260208601611afd565b91505092915050565b60008060008060808587031215611b8757611b86611657565b5b6000611b95878288016118a4565b9450506020611ba6878288016118a4565b9350506040611bb7878288016117ef565b925050606085013567ffffffffffffffff811115611bd857611bd761165c565b5b611be487828801611b3f565b91505092959194509250565b600080600080600060a08688031215611c0c57611c0b611657565b5b6000611c1a888289016118a4565b9550506020611c2b888289016118a4565b9450506040611c3c888289016117ef565b9350506060611c4d888289016117ef565b9250506080611c5e888289016117ef565b9150509295509295909350565b60008060408385031215611c8257611c81611657565b5b6000611c90858286016117ef565b9250506020611ca1858286016118a4565b9150509250929050565b60008060408385031215611cc257611cc1611657565b5b6000611cd0858286016118a4565b9250506020611ce1858286016118a4565b9150509250929050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b60006002820490506001821680611d3257607f821691505b602082108103611d4557611d44611ceb565b5b50919050565b7f4552433732313a20617070726f76656420717565727920666f72206e6f6e657860008201527f697374656e7420746f6b656e0000000000000000000000000000000000000000602082015250565b6000611da7602c83611727565b9150611db282611d4b565b604082019050919050565b60006020820190508181036000830152611dd681611d9a565b9050919050565b7f4552433732313a207472616e736665722063616c6c6572206973206e6f74206f60008201527f776e6572206e6f7220617070726f766564000000000000000000000000000000602082015250565b6000611e39603183611727565b9150611e4482611ddd565b604082019050919050565b60006020820190508181036000830152611e6881611e2c565b9050919050565b7f4552433732313a206f776e657220717565727920666f72206e6f6e657869737460008201527f656e7420746f6b656e0000000000000000000000000000000000000000000000602082015250565b6000611ecb602983611727565b9150611ed682611e6f565b604082019050919050565b60006020820190508181036000830152611efa81611ebe565b9050919050565b7f4552433732313a2062616c616e636520717565727920666f7220746865207a6560008201527f726f206164647265737300000000000000000000000000000000000000000000602082015250565b6000611f5d602a83611727565b9150611f6882611f01565b604082019050919050565b60006020820190508181036000830152611f8c81611f50565b9050919050565b7f4552433732313a20617070726f766520746f2063616c6c657200000000000000600082015250565b6000611fc9601983611727565b9150611fd482611f93565b602082019050919050565b60006020820190508181036000830152611ff881611fbc565b9050919050565b7f4552433732313a206d696e7420746f20746865207a65726f2061646472657373600082015250565b6000612035602083611727565b915061204082611fff565b602082019050919050565b6000602082019050818103600083015261206481612028565b9050919050565b7f4552433732313a20746f6b656e20616c7265616479206d696e74656400000000600082015250565b60006120a1601c83611727565b91506120ac8261206b565b602082019050919050565b600060208201905081810360008301526120d081612094565b9050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b6000612111826117ce565b915061211c836117ce565b9250828201905080821115612134576121336120d7565b5b92915050565b600060408201905061214f600083018561194c565b61215c6020830184611863565b9392505050565b7f776569676874206d757374206265206265747765656e203020616e6420313030600082015250565b6000612199602083611727565b91506121a482612163565b602082019050919050565b600060208201905081810360008301526121c88161218c565b9050919050565b60006121da826117ce565b91506121e5836117ce565b92508282039050818111156121fd576121fc6120d7565b5b92915050565b7f4552433732313a207472616e736665722066726f6d20696e636f72726563742060008201527f6f776e6572000000000000000000000000000000000000000000000000000000602082015250565b600061225f602583611727565b915061226a82612203565b604082019050919050565b6000602082019050818103600083015261228e81612252565b9050919050565b7f4552433732313a207472616e7366657220746f20746865207a65726f2061646460008201527f7265737300000000000000000000000000000000000000000000000000000000602082015250565b60006122f1602483611727565b91506122fc82612295565b604082019050919050565b60006020820190508181036000830152612320816122e4565b9050919050565b7f4552433732313a207472616e7366657220746f206e6f6e20455243373231526560008201527f63656976657220696d706c656d656e7465720000000000000000000000000000602082015250565b6000612383603283611727565b915061238e82612327565b604082019050919050565b600060208201905081810360008301526123b281612376565b9050919050565b600081519050919050565b600082825260208201905092915050565b60006123e0826123b9565b6123ea81856123c4565b93506123fa818560208601611738565b61240381611762565b840191505092915050565b60006080820190506124236000830187611863565b6124306020830186611863565b61243d604083018561194c565b818103606083015261244f81846123d5565b905095945050505050565b600081905092915050565b6000612470826123b9565b61247a818561245a565b935061248a818560208601611738565b80840191505092915050565b60006124a28284612465565b915081905092915050565b6000815190506124bc8161168d565b92915050565b6000602082840312156124d8576124d7611657565b5b60006124e6848285016124ad565b9150509291505056fea26469706673582212204e7472793c7e385dbc5f871c474d150c7904748d796d64ddeb8a8b4ed9d6be9b64736f6c634300081e0033",
 }
 
 // Pxa721ABI is the input ABI used to generate the binding from.
 // Deprecated: Use Pxa721MetaData.ABI instead.
 var Pxa721ABI = Pxa721MetaData.ABI
 
+// Pxa721Bin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use Pxa721MetaData.Bin instead.
+var Pxa721Bin = Pxa721MetaData.Bin
+
+// DeployPxa721 deploys a new Ethereum contract, binding an instance of Pxa721 to it.
+func DeployPxa721(auth *bind.TransactOpts, backend bind.ContractBackend, _name string) (common.Address, *types.Transaction, *Pxa721, error) {
+	parsed, err := Pxa721MetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(Pxa721Bin), backend, _name)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &Pxa721{Pxa721Caller: Pxa721Caller{contract: contract}, Pxa721Transactor: Pxa721Transactor{contract: contract}, Pxa721Filterer: Pxa721Filterer{contract: contract}}, nil
+}
+
 // Pxa721 is an auto generated Go binding around an Ethereum contract.
 type Pxa721 struct {
 	Pxa721Caller     // Read-only binding to the contract
@@ -180,6 +202,82 @@ func (_Pxa721 *Pxa721TransactorRaw) Transact(opts *bind.TransactOpts, method str
 	return _Pxa721.Contract.contract.Transact(opts, method, params...)
 }
 
+// TokenPrice is a free data retrieval call binding the contract method 0x6866d144.
+//
+// Solidity: function _tokenPrice(uint256 ) view returns(uint256)
+func (_Pxa721 *Pxa721Caller) TokenPrice(opts *bind.CallOpts, arg0 *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _Pxa721.contract.Call(opts, &out, "_tokenPrice", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// TokenPrice is a free data retrieval call binding the contract method 0x6866d144.
+//
+// Solidity: function _tokenPrice(uint256 ) view returns(uint256)
+func (_Pxa721 *Pxa721Session) TokenPrice(arg0 *big.Int) (*big.Int, error) {
+	return _Pxa721.Contract.TokenPrice(&_Pxa721.CallOpts, arg0)
+}
+
+// TokenPrice is a free data retrieval call binding the contract method 0x6866d144.
+//
+// Solidity: function _tokenPrice(uint256 ) view returns(uint256)
+func (_Pxa721 *Pxa721CallerSession) TokenPrice(arg0 *big.Int) (*big.Int, error) {
+	return _Pxa721.Contract.TokenPrice(&_Pxa721.CallOpts, arg0)
+}
+
+// TokenSplitAsset is a free data retrieval call binding the contract method 0x2b580117.
+//
+// Solidity: function _tokenSplitAsset(uint256 ) view returns(uint256 weight, uint256 orgTokenID)
+func (_Pxa721 *Pxa721Caller) TokenSplitAsset(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	Weight     *big.Int
+	OrgTokenID *big.Int
+}, error) {
+	var out []interface{}
+	err := _Pxa721.contract.Call(opts, &out, "_tokenSplitAsset", arg0)
+
+	outstruct := new(struct {
+		Weight     *big.Int
+		OrgTokenID *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Weight = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.OrgTokenID = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// TokenSplitAsset is a free data retrieval call binding the contract method 0x2b580117.
+//
+// Solidity: function _tokenSplitAsset(uint256 ) view returns(uint256 weight, uint256 orgTokenID)
+func (_Pxa721 *Pxa721Session) TokenSplitAsset(arg0 *big.Int) (struct {
+	Weight     *big.Int
+	OrgTokenID *big.Int
+}, error) {
+	return _Pxa721.Contract.TokenSplitAsset(&_Pxa721.CallOpts, arg0)
+}
+
+// TokenSplitAsset is a free data retrieval call binding the contract method 0x2b580117.
+//
+// Solidity: function _tokenSplitAsset(uint256 ) view returns(uint256 weight, uint256 orgTokenID)
+func (_Pxa721 *Pxa721CallerSession) TokenSplitAsset(arg0 *big.Int) (struct {
+	Weight     *big.Int
+	OrgTokenID *big.Int
+}, error) {
+	return _Pxa721.Contract.TokenSplitAsset(&_Pxa721.CallOpts, arg0)
+}
+
 // BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
 //
 // Solidity: function balanceOf(address owner) view returns(uint256)
@@ -244,7 +342,7 @@ func (_Pxa721 *Pxa721CallerSession) GetApproved(tokenId *big.Int) (common.Addres
 
 // GetSplitToken is a free data retrieval call binding the contract method 0xd86af763.
 //
-// Solidity: function getSplitToken(uint256 orgTokenID, address owner) view returns(uint256)
+// Solidity: function getSplitToken(uint256 orgTokenID, address owner) pure returns(uint256)
 func (_Pxa721 *Pxa721Caller) GetSplitToken(opts *bind.CallOpts, orgTokenID *big.Int, owner common.Address) (*big.Int, error) {
 	var out []interface{}
 	err := _Pxa721.contract.Call(opts, &out, "getSplitToken", orgTokenID, owner)
@@ -261,14 +359,14 @@ func (_Pxa721 *Pxa721Caller) GetSplitToken(opts *bind.CallOpts, orgTokenID *big.
 
 // GetSplitToken is a free data retrieval call binding the contract method 0xd86af763.
 //
-// Solidity: function getSplitToken(uint256 orgTokenID, address owner) view returns(uint256)
+// Solidity: function getSplitToken(uint256 orgTokenID, address owner) pure returns(uint256)
 func (_Pxa721 *Pxa721Session) GetSplitToken(orgTokenID *big.Int, owner common.Address) (*big.Int, error) {
 	return _Pxa721.Contract.GetSplitToken(&_Pxa721.CallOpts, orgTokenID, owner)
 }
 
 // GetSplitToken is a free data retrieval call binding the contract method 0xd86af763.
 //
-// Solidity: function getSplitToken(uint256 orgTokenID, address owner) view returns(uint256)
+// Solidity: function getSplitToken(uint256 orgTokenID, address owner) pure returns(uint256)
 func (_Pxa721 *Pxa721CallerSession) GetSplitToken(orgTokenID *big.Int, owner common.Address) (*big.Int, error) {
 	return _Pxa721.Contract.GetSplitToken(&_Pxa721.CallOpts, orgTokenID, owner)
 }
@@ -304,6 +402,37 @@ func (_Pxa721 *Pxa721CallerSession) IsApprovedForAll(owner common.Address, opera
 	return _Pxa721.Contract.IsApprovedForAll(&_Pxa721.CallOpts, owner, operator)
 }
 
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_Pxa721 *Pxa721Caller) Name(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _Pxa721.contract.Call(opts, &out, "name")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_Pxa721 *Pxa721Session) Name() (string, error) {
+	return _Pxa721.Contract.Name(&_Pxa721.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_Pxa721 *Pxa721CallerSession) Name() (string, error) {
+	return _Pxa721.Contract.Name(&_Pxa721.CallOpts)
+}
+
 // OwnerOf is a free data retrieval call binding the contract method 0x6352211e.
 //
 // Solidity: function ownerOf(uint256 tokenId) view returns(address)
@@ -337,7 +466,7 @@ func (_Pxa721 *Pxa721CallerSession) OwnerOf(tokenId *big.Int) (common.Address, e
 
 // SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
 //
-// Solidity: function supportsInterface(bytes4 interfaceId) view returns(bool)
+// Solidity: function supportsInterface(bytes4 interfaceId) pure returns(bool)
 func (_Pxa721 *Pxa721Caller) SupportsInterface(opts *bind.CallOpts, interfaceId [4]byte) (bool, error) {
 	var out []interface{}
 	err := _Pxa721.contract.Call(opts, &out, "supportsInterface", interfaceId)
@@ -354,14 +483,14 @@ func (_Pxa721 *Pxa721Caller) SupportsInterface(opts *bind.CallOpts, interfaceId
 
 // SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
 //
-// Solidity: function supportsInterface(bytes4 interfaceId) view returns(bool)
+// Solidity: function supportsInterface(bytes4 interfaceId) pure returns(bool)
 func (_Pxa721 *Pxa721Session) SupportsInterface(interfaceId [4]byte) (bool, error) {
 	return _Pxa721.Contract.SupportsInterface(&_Pxa721.CallOpts, interfaceId)
 }
 
 // SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
 //
-// Solidity: function supportsInterface(bytes4 interfaceId) view returns(bool)
+// Solidity: function supportsInterface(bytes4 interfaceId) pure returns(bool)
 func (_Pxa721 *Pxa721CallerSession) SupportsInterface(interfaceId [4]byte) (bool, error) {
 	return _Pxa721.Contract.SupportsInterface(&_Pxa721.CallOpts, interfaceId)
 }
@@ -494,23 +623,23 @@ func (_Pxa721 *Pxa721TransactorSession) TransferFrom(from common.Address, to com
 
 // UploadMint is a paid mutator transaction binding the contract method 0xa2dc66de.
 //
-// Solidity: function uploadMint(address to, uint256 tokenID) returns()
-func (_Pxa721 *Pxa721Transactor) UploadMint(opts *bind.TransactOpts, to common.Address, tokenID *big.Int) (*types.Transaction, error) {
-	return _Pxa721.contract.Transact(opts, "uploadMint", to, tokenID)
+// Solidity: function uploadMint(address to, uint256 tokenId) returns()
+func (_Pxa721 *Pxa721Transactor) UploadMint(opts *bind.TransactOpts, to common.Address, tokenId *big.Int) (*types.Transaction, error) {
+	return _Pxa721.contract.Transact(opts, "uploadMint", to, tokenId)
 }
 
 // UploadMint is a paid mutator transaction binding the contract method 0xa2dc66de.
 //
-// Solidity: function uploadMint(address to, uint256 tokenID) returns()
-func (_Pxa721 *Pxa721Session) UploadMint(to common.Address, tokenID *big.Int) (*types.Transaction, error) {
-	return _Pxa721.Contract.UploadMint(&_Pxa721.TransactOpts, to, tokenID)
+// Solidity: function uploadMint(address to, uint256 tokenId) returns()
+func (_Pxa721 *Pxa721Session) UploadMint(to common.Address, tokenId *big.Int) (*types.Transaction, error) {
+	return _Pxa721.Contract.UploadMint(&_Pxa721.TransactOpts, to, tokenId)
 }
 
 // UploadMint is a paid mutator transaction binding the contract method 0xa2dc66de.
 //
-// Solidity: function uploadMint(address to, uint256 tokenID) returns()
-func (_Pxa721 *Pxa721TransactorSession) UploadMint(to common.Address, tokenID *big.Int) (*types.Transaction, error) {
-	return _Pxa721.Contract.UploadMint(&_Pxa721.TransactOpts, to, tokenID)
+// Solidity: function uploadMint(address to, uint256 tokenId) returns()
+func (_Pxa721 *Pxa721TransactorSession) UploadMint(to common.Address, tokenId *big.Int) (*types.Transaction, error) {
+	return _Pxa721.Contract.UploadMint(&_Pxa721.TransactOpts, to, tokenId)
 }
 
 // Pxa721ApprovalIterator is returned from FilterApproval and is used to iterate over the raw logs and unpacked data for Approval events raised by the Pxa721 contract.
@@ -31,13 +31,35 @@ var (
 
 // Pxc20MetaData contains all meta data concerning the Pxc20 contract.
 var Pxc20MetaData = &bind.MetaData{
-	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_sym\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	ABI: "[{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_name\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_symbol\",\"type\":\"string\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Approval\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"ownerAddr\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"}],\"name\":\"allowance\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"spender\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"approve\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"mint\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transferFrom\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561001057600080fd5b506040516117323803806117328339818101604052810190610032919061022b565b816003908161004191906104c4565b50806004908161005191906104c4565b506012600560006101000a81548160ff021916908360ff16021790555033600560016101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055505050610596565b6000604051905090565b600080fd5b600080fd5b600080fd5b600080fd5b6000601f19601f8301169050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052604160045260246000fd5b61011d826100d4565b810181811067ffffffffffffffff8211171561013c5761013b6100e5565b5b80604052505050565b600061014f6100b6565b905061015b8282610114565b919050565b600067ffffffffffffffff82111561017b5761017a6100e5565b5b610184826100d4565b9050602081019050919050565b60005b838110156101af578082015181840152602081019050610194565b60008484015250505050565b60006101ce6101c984610160565b610145565b9050828152602081018484840111156101ea576101e96100cf565b5b6101f5848285610191565b509392505050565b600082601f830112610212576102116100ca565b5b81516102228482602086016101bb565b91505092915050565b60008060408385031215610242576102416100c0565b5b600083015167ffffffffffffffff8111156102605761025f6100c5565b5b61026c858286016101fd565b925050602083015167ffffffffffffffff81111561028d5761028c6100c5565b5b610299858286016101fd565b9150509250929050565b600081519050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b600060028204905060018216806102f557607f821691505b602082108103610308576103076102ae565b5b50919050565b60008190508160005260206000209050919050565b60006020601f8301049050919050565b600082821b905092915050565b6000600883026103707fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff82610333565b61037a8683610333565b95508019841693508086168417925050509392505050565b6000819050919050565b6000819050919050565b60006103c16103bc6103b784610392565b61039c565b610392565b9050919050565b6000819050919050565b6103db836103a6565b6103ef6103e7826103c8565b848454610340565b825550505050565b600090565b6104046103f7565b61040f8184846103d2565b505050565b5b81811015610433576104286000826103fc565b600181019050610415565b5050565b601f821115610478576104498161030e565b61045284610323565b81016020851015610461578190505b61047561046d85610323565b830182610414565b50505b505050565b600082821c905092915050565b600061049b6000198460080261047d565b1980831691505092915050565b60006104b4838361048a565b9150826002028217905092915050565b6104cd826102a3565b67ffffffffffffffff8111156104e6576104e56100e5565b5b6104f082546102dd565b6104fb828285610437565b600060209050601f83116001811461052e576000841561051c578287015190505b61052685826104a8565b86555061058e565b601f19841661053c8661030e565b60005b828110156105645784890151825560018201915060208501945060208101905061053f565b86831015610581578489015161057d601f89168261048a565b8355505b6001600288020188555050505b505050505050565b61118d806105a56000396000f3fe608060405234801561001057600080fd5b506004361061009e5760003560e01c806340c10f191161006657806340c10f191461015d57806370a082311461017957806395d89b41146101a9578063a9059cbb146101c7578063dd62ed3e146101f75761009e565b806306fdde03146100a3578063095ea7b3146100c157806318160ddd146100f157806323b872dd1461010f578063313ce5671461013f575b600080fd5b6100ab610227565b6040516100b89190610c5e565b60405180910390f35b6100db60048036038101906100d69190610d19565b6102b5565b6040516100e89190610d74565b60405180910390f35b6100f9610415565b6040516101069190610d9e565b60405180910390f35b61012960048036038101906101249190610db9565b61041f565b6040516101369190610d74565b60405180910390f35b61014761077c565b6040516101549190610e28565b60405180910390f35b61017760048036038101906101729190610d19565b61078f565b005b610193600480360381019061018e9190610e43565b610867565b6040516101a09190610d9e565b60405180910390f35b6101b16108af565b6040516101be9190610c5e565b60405180910390f35b6101e160048036038101906101dc9190610d19565b61093d565b6040516101ee9190610d74565b60405180910390f35b610211600480360381019061020c9190610e70565b610b47565b60405161021e9190610d9e565b60405180910390f35b6003805461023490610edf565b80601f016020809104026020016040519081016040528092919081815260200182805461026090610edf565b80156102ad5780601f10610282576101008083540402835291602001916102ad565b820191906000526020600020905b81548152906001019060200180831161029057829003601f168201915b505050505081565b60008073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff1603610325576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161031c90610f5c565b60405180910390fd5b81600160003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508273ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925846040516104039190610d9e565b60405180910390a36001905092915050565b6000600254905090565b60008073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff160361048f576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161048690610fc8565b60405180910390fd5b816000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020541015610510576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161050790611034565b60405180910390fd5b81600160008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000205410156105cf576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016105c6906110a0565b60405180910390fd5b816000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020600082825461061d91906110ef565b92505081905550816000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008282546106729190611123565b9250508190555081600160008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020600082825461070591906110ef565b925050819055508273ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef846040516107699190610d9e565b60405180910390a3600190509392505050565b600560009054906101000a900460ff1681565b80600260008282546107a19190611123565b92505081905550806000808473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008282546107f69190611123565b925050819055508173ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef8360405161085b9190610d9e565b60405180910390a35050565b60008060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020549050919050565b600480546108bc90610edf565b80601f01602080910402602001604051908101604052809291908181526020018280546108e890610edf565b80156109355780601f1061090a57610100808354040283529160200191610935565b820191906000526020600020905b81548152906001019060200180831161091857829003601f168201915b505050505081565b60008073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff16036109ad576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016109a490610fc8565b60405180910390fd5b816000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020541015610a2e576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610a2590611034565b60405180910390fd5b816000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000206000828254610a7c91906110ef565b92505081905550816000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000206000828254610ad19190611123565b925050819055508273ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef84604051610b359190610d9e565b60405180910390a36001905092915050565b6000600160008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905092915050565b600081519050919050565b600082825260208201905092915050565b60005b83811015610c08578082015181840152602081019050610bed565b60008484015250505050565b6000601f19601f8301169050919050565b6000610c3082610bce565b610c3a8185610bd9565b9350610c4a818560208601610bea565b610c5381610c14565b840191505092915050565b60006020820190508181036000830152610c788184610c25565b905092915050565b600080fd5b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b6000610cb082610c85565b9050919050565b610cc081610ca5565b8114610ccb57600080fd5b50565b600081359050610cdd81610cb7565b92915050565b6000819050919050565b610cf681610ce3565b8114610d0157600080fd5b50565b600081359050610d1381610ced565b92915050565b60008060408385031215610d3057610d2f610c80565b5b6000610d3e85828601610cce565b9250506020610d4f85828601610d04565b9150509250929050565b60008115159050919050565b610d6e81610d59565b82525050565b6000602082019050610d896000830184610d65565b92915050565b610d9881610ce3565b82525050565b6000602082019050610db36000830184610d8f565b92915050565b600080600060608486031215610dd257610dd1610c80565b5b6000610de086828701610cce565b9350506020610df186828701610cce565b9250506040610e0286828701610d04565b9150509250925092565b600060ff82169050919050565b610e2281610e0c565b82525050565b6000602082019050610e3d6000830184610e19565b92915050565b600060208284031215610e5957610e58610c80565b5b6000610e6784828501610cce565b91505092915050565b60008060408385031215610e8757610e86610c80565b5b6000610e9585828601610cce565b9250506020610ea685828601610cce565b9150509250929050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052602260045260246000fd5b60006002820490506001821680610ef757607f821691505b602082108103610f0a57610f09610eb0565b5b50919050565b7f43616e6e6f7420617070726f766520746f207a65726f20616464726573730000600082015250565b6000610f46601e83610bd9565b9150610f5182610f10565b602082019050919050565b60006020820190508181036000830152610f7581610f39565b9050919050565b7f5472616e7366657220746f207a65726f20616464726573730000000000000000600082015250565b6000610fb2601883610bd9565b9150610fbd82610f7c565b602082019050919050565b60006020820190508181036000830152610fe181610fa5565b9050919050565b7f496e73756666696369656e742062616c616e6365000000000000000000000000600082015250565b600061101e601483610bd9565b915061102982610fe8565b602082019050919050565b6000602082019050818103600083015261104d81611011565b9050919050565b7f496e73756666696369656e7420616c6c6f77616e636500000000000000000000600082015250565b600061108a601683610bd9565b915061109582611054565b602082019050919050565b600060208201905081810360008301526110b98161107d565b9050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b60006110fa82610ce3565b915061110583610ce3565b925082820390508181111561111d5761111c6110c0565b5b92915050565b600061112e82610ce3565b915061113983610ce3565b9250828201905080821115611151576111506110c0565b5b9291505056fea264697066735822122071ff7bccd729fc2a67915d3e64039e490d71dd0a238696cb2556df903460e1a564736f6c634300081e0033",
 }
 
 // Pxc20ABI is the input ABI used to generate the binding from.
 // Deprecated: Use Pxc20MetaData.ABI instead.
 var Pxc20ABI = Pxc20MetaData.ABI
 
+// Pxc20Bin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use Pxc20MetaData.Bin instead.
+var Pxc20Bin = Pxc20MetaData.Bin
+
+// DeployPxc20 deploys a new Ethereum contract, binding an instance of Pxc20 to it.
+func DeployPxc20(auth *bind.TransactOpts, backend bind.ContractBackend, _name string, _symbol string) (common.Address, *types.Transaction, *Pxc20, error) {
+	parsed, err := Pxc20MetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(Pxc20Bin), backend, _name, _symbol)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &Pxc20{Pxc20Caller: Pxc20Caller{contract: contract}, Pxc20Transactor: Pxc20Transactor{contract: contract}, Pxc20Filterer: Pxc20Filterer{contract: contract}}, nil
+}
+
 // Pxc20 is an auto generated Go binding around an Ethereum contract.
 type Pxc20 struct {
 	Pxc20Caller     // Read-only binding to the contract
@@ -182,10 +204,10 @@ func (_Pxc20 *Pxc20TransactorRaw) Transact(opts *bind.TransactOpts, method strin
 
 // Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
 //
-// Solidity: function allowance(address owner, address spender) view returns(uint256)
-func (_Pxc20 *Pxc20Caller) Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error) {
+// Solidity: function allowance(address ownerAddr, address spender) view returns(uint256)
+func (_Pxc20 *Pxc20Caller) Allowance(opts *bind.CallOpts, ownerAddr common.Address, spender common.Address) (*big.Int, error) {
 	var out []interface{}
-	err := _Pxc20.contract.Call(opts, &out, "allowance", owner, spender)
+	err := _Pxc20.contract.Call(opts, &out, "allowance", ownerAddr, spender)
 
 	if err != nil {
 		return *new(*big.Int), err
@@ -199,24 +221,24 @@ func (_Pxc20 *Pxc20Caller) Allowance(opts *bind.CallOpts, owner common.Address,
 
 // Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
 //
-// Solidity: function allowance(address owner, address spender) view returns(uint256)
-func (_Pxc20 *Pxc20Session) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
-	return _Pxc20.Contract.Allowance(&_Pxc20.CallOpts, owner, spender)
+// Solidity: function allowance(address ownerAddr, address spender) view returns(uint256)
+func (_Pxc20 *Pxc20Session) Allowance(ownerAddr common.Address, spender common.Address) (*big.Int, error) {
+	return _Pxc20.Contract.Allowance(&_Pxc20.CallOpts, ownerAddr, spender)
 }
 
 // Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
 //
-// Solidity: function allowance(address owner, address spender) view returns(uint256)
-func (_Pxc20 *Pxc20CallerSession) Allowance(owner common.Address, spender common.Address) (*big.Int, error) {
-	return _Pxc20.Contract.Allowance(&_Pxc20.CallOpts, owner, spender)
+// Solidity: function allowance(address ownerAddr, address spender) view returns(uint256)
+func (_Pxc20 *Pxc20CallerSession) Allowance(ownerAddr common.Address, spender common.Address) (*big.Int, error) {
+	return _Pxc20.Contract.Allowance(&_Pxc20.CallOpts, ownerAddr, spender)
 }
 
 // BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
 //
-// Solidity: function balanceOf(address owner) view returns(uint256)
-func (_Pxc20 *Pxc20Caller) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_Pxc20 *Pxc20Caller) BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error) {
 	var out []interface{}
-	err := _Pxc20.contract.Call(opts, &out, "balanceOf", owner)
+	err := _Pxc20.contract.Call(opts, &out, "balanceOf", account)
 
 	if err != nil {
 		return *new(*big.Int), err
@@ -230,16 +252,78 @@ func (_Pxc20 *Pxc20Caller) BalanceOf(opts *bind.CallOpts, owner common.Address)
 
 // BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
 //
-// Solidity: function balanceOf(address owner) view returns(uint256)
-func (_Pxc20 *Pxc20Session) BalanceOf(owner common.Address) (*big.Int, error) {
-	return _Pxc20.Contract.BalanceOf(&_Pxc20.CallOpts, owner)
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_Pxc20 *Pxc20Session) BalanceOf(account common.Address) (*big.Int, error) {
+	return _Pxc20.Contract.BalanceOf(&_Pxc20.CallOpts, account)
 }
 
 // BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
 //
-// Solidity: function balanceOf(address owner) view returns(uint256)
-func (_Pxc20 *Pxc20CallerSession) BalanceOf(owner common.Address) (*big.Int, error) {
-	return _Pxc20.Contract.BalanceOf(&_Pxc20.CallOpts, owner)
+// Solidity: function balanceOf(address account) view returns(uint256)
+func (_Pxc20 *Pxc20CallerSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _Pxc20.Contract.BalanceOf(&_Pxc20.CallOpts, account)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_Pxc20 *Pxc20Caller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _Pxc20.contract.Call(opts, &out, "decimals")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_Pxc20 *Pxc20Session) Decimals() (uint8, error) {
+	return _Pxc20.Contract.Decimals(&_Pxc20.CallOpts)
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+//
+// Solidity: function decimals() view returns(uint8)
+func (_Pxc20 *Pxc20CallerSession) Decimals() (uint8, error) {
+	return _Pxc20.Contract.Decimals(&_Pxc20.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_Pxc20 *Pxc20Caller) Name(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _Pxc20.contract.Call(opts, &out, "name")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_Pxc20 *Pxc20Session) Name() (string, error) {
+	return _Pxc20.Contract.Name(&_Pxc20.CallOpts)
+}
+
+// Name is a free data retrieval call binding the contract method 0x06fdde03.
+//
+// Solidity: function name() view returns(string)
+func (_Pxc20 *Pxc20CallerSession) Name() (string, error) {
+	return _Pxc20.Contract.Name(&_Pxc20.CallOpts)
 }
 
 // Symbol is a free data retrieval call binding the contract method 0x95d89b41.
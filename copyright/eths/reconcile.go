@@ -0,0 +1,131 @@
+package eths
+
+import (
+	"copyright/dbs"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 股权份额差异：数据库登记与链上拆分份额不一致
+type Discrepancy struct {
+	TokenID     string `json:"token_id"`     //原始tokenid
+	Address     string `json:"address"`      //持有人地址
+	DBWeight    int64  `json:"db_weight"`    //t_equity_registration中的份额合计
+	ChainWeight int64  `json:"chain_weight"` //合约_tokenSplitAsset中的份额
+	Pending     bool   `json:"pending"`      //该token存在未确认的交割，差异可能是暂时的
+	Repaired    bool   `json:"repaired"`     //是否已按链上数据修复
+}
+
+// 判断地址是否为本链管理员
+func (ch *Chain) IsAdmin(address string) bool {
+	return common.HexToAddress(address) == common.HexToAddress(ch.adminAddr)
+}
+
+// 计算拆分token id，与合约getSplitToken一致：keccak256(abi.encode(orgTokenID, owner))
+func splitTokenID(orgTokenID *big.Int, owner common.Address) *big.Int {
+	hash := crypto.Keccak256(common.LeftPadBytes(orgTokenID.Bytes(), 32), common.LeftPadBytes(owner.Bytes(), 32))
+	return new(big.Int).SetBytes(hash)
+}
+
+// 对账：逐个核对数据库登记的股权与链上拆分份额，tokenID为空时核对全部token
+func (ch *Chain) Reconcile(tokenID string) ([]Discrepancy, error) {
+	//1. 汇总数据库中的股权份额
+	holders, err := dbs.QueryEquityHolders(ch.ChainID(), tokenID)
+	if err != nil {
+		return nil, err
+	}
+	dbWeights := map[string]map[common.Address]int64{}
+	for _, h := range holders {
+		if dbWeights[h.TokenID] == nil {
+			dbWeights[h.TokenID] = map[common.Address]int64{}
+		}
+		dbWeights[h.TokenID][common.HexToAddress(h.Address)] = h.Weight
+	}
+	//2. 根据索引的Transfer事件找出链上收到过拆分token的地址，发现数据库未登记的持有人
+	targets, err := dbs.QueryTransferTargets(ch.ChainID(), common.HexToAddress(ch.pxaAddr).Hex())
+	if err != nil {
+		return nil, err
+	}
+	received := map[string]bool{}
+	receivers := map[common.Address]bool{}
+	for _, t := range targets {
+		addr := common.HexToAddress(t.To)
+		received[addr.Hex()+"|"+t.Value] = true
+		receivers[addr] = true
+	}
+	result := []Discrepancy{}
+	pending := map[string]bool{}
+	for token, weights := range dbWeights {
+		orgTokenID, ok := new(big.Int).SetString(token, 10)
+		if !ok {
+			fmt.Println("invalid token_id in t_equity_registration", token)
+			continue
+		}
+		candidates := map[common.Address]int64{}
+		for addr, w := range weights {
+			candidates[addr] = w
+		}
+		for addr := range receivers {
+			if _, ok := candidates[addr]; !ok && received[addr.Hex()+"|"+splitTokenID(orgTokenID, addr).String()] {
+				candidates[addr] = 0
+			}
+		}
+		for addr, dbWeight := range candidates {
+			//3. 读取链上份额
+			_, weight, err := ch.GetShares(orgTokenID, addr.Hex())
+			if err != nil {
+				return nil, err
+			}
+			if weight.Cmp(big.NewInt(dbWeight)) == 0 {
+				continue
+			}
+			//4. 记录差异，并标记存在未确认交割的token
+			if _, ok := pending[token]; !ok {
+				count, err := dbs.CountPendingTrades(token)
+				if err != nil {
+					return nil, err
+				}
+				pending[token] = count > 0
+			}
+			result = append(result, Discrepancy{
+				TokenID:     token,
+				Address:     addr.Hex(),
+				DBWeight:    dbWeight,
+				ChainWeight: weight.Int64(),
+				Pending:     pending[token],
+			})
+		}
+	}
+	// 按token和地址排序，保证报告稳定
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TokenID != result[j].TokenID {
+			return result[i].TokenID < result[j].TokenID
+		}
+		return result[i].Address < result[j].Address
+	})
+	return result, nil
+}
+
+// 按链上份额修复数据库：追加一条调整记录使登记合计与链上一致，存在未确认交割的token跳过
+func (ch *Chain) RepairEquity(discrepancies []Discrepancy) error {
+	for i, d := range discrepancies {
+		if d.Pending {
+			continue
+		}
+		er := dbs.EquityRegistration{
+			Address: d.Address,
+			TokenID: d.TokenID,
+			Weight:  d.ChainWeight - d.DBWeight,
+		}
+		if err := er.AddEquityRegistration(); err != nil {
+			return err
+		}
+		discrepancies[i].Repaired = true
+		fmt.Printf("repaired equity of token %s for %s: %d -> %d\n", d.TokenID, d.Address, d.DBWeight, d.ChainWeight)
+	}
+	return nil
+}
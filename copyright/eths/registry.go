@@ -0,0 +1,33 @@
+package eths
+
+import (
+	"context"
+	"copyright/configs"
+	"fmt"
+)
+
+// 根据节点的链ID从合约登记中读取合约地址，未登记时沿用默认地址
+func (ch *Chain) loadContracts() error {
+	id, err := ch.cli.ChainID(context.Background())
+	if err != nil {
+		fmt.Println("Failed to get chainId", err)
+		return err
+	}
+	ch.ID = id
+	c, ok := configs.Conf.Contracts[id.String()]
+	if !ok {
+		fmt.Printf("no contracts registered for chain %s, use default addresses\n", id)
+		return nil
+	}
+	if c.PXC20 != "" {
+		ch.pxcAddr = c.PXC20
+	}
+	if c.PXA721 != "" {
+		ch.pxaAddr = c.PXA721
+	}
+	if c.Settlement != "" {
+		ch.settleAddr = c.Settlement
+	}
+	ch.deployBlock = c.DeployBlock
+	return nil
+}
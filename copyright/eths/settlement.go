@@ -0,0 +1,483 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package eths
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SettlementMetaData contains all meta data concerning the Settlement contract.
+var SettlementMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_pxc\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_pxa\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"_operator\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"royalty\",\"type\":\"uint256\"}],\"name\":\"Settled\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"operator\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"pxa\",\"outputs\":[{\"internalType\":\"contractIPXA721\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"pxc\",\"outputs\":[{\"internalType\":\"contractIPXC20\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"buyer\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"seller\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"tokenId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"weight\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"price\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"royalty\",\"type\":\"uint256\"}],\"name\":\"settle\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561001057600080fd5b50604051610e37380380610e37833981810160405281019061003291906101ce565b600073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16036100a1576040517f08c379a0000000000000000000000000000000000000000000000000000000008152600401610098906102a4565b60405180910390fd5b826000806101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555081600160006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555080600260006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055505050506102c4565b600080fd5b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b600061019b82610170565b9050919050565b6101ab81610190565b81146101b657600080fd5b50565b6000815190506101c8816101a2565b92915050565b6000806000606084860312156101e7576101e661016b565b5b60006101f5868287016101b9565b9350506020610206868287016101b9565b9250506040610217868287016101b9565b9150509250925092565b600082825260208201905092915050565b7f536574746c656d656e743a206f70657261746f7220697320746865207a65726f60008201527f2061646472657373000000000000000000000000000000000000000000000000602082015250565b600061028e602883610221565b915061029982610232565b604082019050919050565b600060208201905081810360008301526102bd81610281565b9050919050565b610b64806102d36000396000f3fe608060405234801561001057600080fd5b506004361061004c5760003560e01c8063570ca735146100515780635cd917f21461006f5780636cf9c6811461008d578063ce509f8d146100a9575b600080fd5b6100596100c7565b6040516100669190610539565b60405180910390f35b6100776100ed565b60405161008491906105b3565b60405180910390f35b6100a760048036038101906100a29190610635565b610111565b005b6100b16104d2565b6040516100be91906106f8565b60405180910390f35b600260009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b60008054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b600260009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff16146101a1576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161019890610796565b60405180910390fd5b600083856101af91906107e5565b9050808211156101f4576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016101eb90610899565b60405180910390fd5b60008054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff166323b872dd8989858561023e91906108b9565b6040518463ffffffff1660e01b815260040161025c939291906108fc565b6020604051808303816000875af115801561027b573d6000803e3d6000fd5b505050506040513d601f19601f8201168201806040525081019061029f919061096b565b6102de576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016102d5906109e4565b60405180910390fd5b60008211156103c75760008054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff166323b872dd8985856040518463ffffffff1660e01b8152600401610344939291906108fc565b6020604051808303816000875af1158015610363573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610387919061096b565b6103c6576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016103bd90610a76565b60405180910390fd5b5b600160009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1663c8e79b20888a8989896040518663ffffffff1660e01b815260040161042a959493929190610a96565b600060405180830381600087803b15801561044457600080fd5b505af1158015610458573d6000803e3d6000fd5b50505050858773ffffffffffffffffffffffffffffffffffffffff168973ffffffffffffffffffffffffffffffffffffffff167ff8ccc60cdc1745fe7f26bac50b420352095b56dcb5e219294aabe445e48e4276888888886040516104c09493929190610ae9565b60405180910390a45050505050505050565b600160009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b600073ffffffffffffffffffffffffffffffffffffffff82169050919050565b6000610523826104f8565b9050919050565b61053381610518565b82525050565b600060208201905061054e600083018461052a565b92915050565b6000819050919050565b600061057961057461056f846104f8565b610554565b6104f8565b9050919050565b600061058b8261055e565b9050919050565b600061059d82610580565b9050919050565b6105ad81610592565b82525050565b60006020820190506105c860008301846105a4565b92915050565b600080fd5b6105dc81610518565b81146105e757600080fd5b50565b6000813590506105f9816105d3565b92915050565b6000819050919050565b610612816105ff565b811461061d57600080fd5b50565b60008135905061062f81610609565b92915050565b600080600080600080600060e0888a031215610654576106536105ce565b5b60006106628a828b016105ea565b97505060206106738a828b016105ea565b96505060406106848a828b01610620565b95505060606106958a828b01610620565b94505060806106a68a828b01610620565b93505060a06106b78a828b016105ea565b92505060c06106c88a828b01610620565b91505092959891949750929550565b60006106e282610580565b9050919050565b6106f2816106d7565b82525050565b600060208201905061070d60008301846106e9565b92915050565b600082825260208201905092915050565b7f536574746c656d656e743a2063616c6c6572206973206e6f7420746865206f7060008201527f657261746f720000000000000000000000000000000000000000000000000000602082015250565b6000610780602683610713565b915061078b82610724565b604082019050919050565b600060208201905081810360008301526107af81610773565b9050919050565b7f4e487b7100000000000000000000000000000000000000000000000000000000600052601160045260246000fd5b60006107f0826105ff565b91506107fb836105ff565b9250828202610809816105ff565b915082820484148315176108205761081f6107b6565b5b5092915050565b7f536574746c656d656e743a20726f79616c7479206578636565647320746f746160008201527f6c00000000000000000000000000000000000000000000000000000000000000602082015250565b6000610883602183610713565b915061088e82610827565b604082019050919050565b600060208201905081810360008301526108b281610876565b9050919050565b60006108c4826105ff565b91506108cf836105ff565b92508282039050818111156108e7576108e66107b6565b5b92915050565b6108f6816105ff565b82525050565b6000606082019050610911600083018661052a565b61091e602083018561052a565b61092b60408301846108ed565b949350505050565b60008115159050919050565b61094881610933565b811461095357600080fd5b50565b6000815190506109658161093f565b92915050565b600060208284031215610981576109806105ce565b5b600061098f84828501610956565b91505092915050565b7f536574746c656d656e743a207061796d656e74206661696c6564000000000000600082015250565b60006109ce601a83610713565b91506109d982610998565b602082019050919050565b600060208201905081810360008301526109fd816109c1565b9050919050565b7f536574746c656d656e743a20726f79616c7479207061796d656e74206661696c60008201527f6564000000000000000000000000000000000000000000000000000000000000602082015250565b6000610a60602283610713565b9150610a6b82610a04565b604082019050919050565b60006020820190508181036000830152610a8f81610a53565b9050919050565b600060a082019050610aab600083018861052a565b610ab8602083018761052a565b610ac560408301866108ed565b610ad260608301856108ed565b610adf60808301846108ed565b9695505050505050565b6000608082019050610afe60008301876108ed565b610b0b60208301866108ed565b610b18604083018561052a565b610b2560608301846108ed565b9594505050505056fea2646970667358221220a1916c25e8ab069ba8f94c256b952ced314baffc830855603a6a80641cf412da64736f6c634300081e0033",
+}
+
+// SettlementABI is the input ABI used to generate the binding from.
+// Deprecated: Use SettlementMetaData.ABI instead.
+var SettlementABI = SettlementMetaData.ABI
+
+// SettlementBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use SettlementMetaData.Bin instead.
+var SettlementBin = SettlementMetaData.Bin
+
+// DeploySettlement deploys a new Ethereum contract, binding an instance of Settlement to it.
+func DeploySettlement(auth *bind.TransactOpts, backend bind.ContractBackend, _pxc common.Address, _pxa common.Address, _operator common.Address) (common.Address, *types.Transaction, *Settlement, error) {
+	parsed, err := SettlementMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(SettlementBin), backend, _pxc, _pxa, _operator)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &Settlement{SettlementCaller: SettlementCaller{contract: contract}, SettlementTransactor: SettlementTransactor{contract: contract}, SettlementFilterer: SettlementFilterer{contract: contract}}, nil
+}
+
+// Settlement is an auto generated Go binding around an Ethereum contract.
+type Settlement struct {
+	SettlementCaller     // Read-only binding to the contract
+	SettlementTransactor // Write-only binding to the contract
+	SettlementFilterer   // Log filterer for contract events
+}
+
+// SettlementCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SettlementCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SettlementTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SettlementTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SettlementFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SettlementFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SettlementSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SettlementSession struct {
+	Contract     *Settlement       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SettlementCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SettlementCallerSession struct {
+	Contract *SettlementCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// SettlementTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SettlementTransactorSession struct {
+	Contract     *SettlementTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// SettlementRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SettlementRaw struct {
+	Contract *Settlement // Generic contract binding to access the raw methods on
+}
+
+// SettlementCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SettlementCallerRaw struct {
+	Contract *SettlementCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SettlementTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SettlementTransactorRaw struct {
+	Contract *SettlementTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSettlement creates a new instance of Settlement, bound to a specific deployed contract.
+func NewSettlement(address common.Address, backend bind.ContractBackend) (*Settlement, error) {
+	contract, err := bindSettlement(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Settlement{SettlementCaller: SettlementCaller{contract: contract}, SettlementTransactor: SettlementTransactor{contract: contract}, SettlementFilterer: SettlementFilterer{contract: contract}}, nil
+}
+
+// NewSettlementCaller creates a new read-only instance of Settlement, bound to a specific deployed contract.
+func NewSettlementCaller(address common.Address, caller bind.ContractCaller) (*SettlementCaller, error) {
+	contract, err := bindSettlement(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementCaller{contract: contract}, nil
+}
+
+// NewSettlementTransactor creates a new write-only instance of Settlement, bound to a specific deployed contract.
+func NewSettlementTransactor(address common.Address, transactor bind.ContractTransactor) (*SettlementTransactor, error) {
+	contract, err := bindSettlement(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementTransactor{contract: contract}, nil
+}
+
+// NewSettlementFilterer creates a new log filterer instance of Settlement, bound to a specific deployed contract.
+func NewSettlementFilterer(address common.Address, filterer bind.ContractFilterer) (*SettlementFilterer, error) {
+	contract, err := bindSettlement(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementFilterer{contract: contract}, nil
+}
+
+// bindSettlement binds a generic wrapper to an already deployed contract.
+func bindSettlement(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SettlementMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Settlement *SettlementRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Settlement.Contract.SettlementCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Settlement *SettlementRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Settlement.Contract.SettlementTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Settlement *SettlementRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Settlement.Contract.SettlementTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Settlement *SettlementCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Settlement.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Settlement *SettlementTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Settlement.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Settlement *SettlementTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Settlement.Contract.contract.Transact(opts, method, params...)
+}
+
+// Operator is a free data retrieval call binding the contract method 0x570ca735.
+//
+// Solidity: function operator() view returns(address)
+func (_Settlement *SettlementCaller) Operator(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "operator")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Operator is a free data retrieval call binding the contract method 0x570ca735.
+//
+// Solidity: function operator() view returns(address)
+func (_Settlement *SettlementSession) Operator() (common.Address, error) {
+	return _Settlement.Contract.Operator(&_Settlement.CallOpts)
+}
+
+// Operator is a free data retrieval call binding the contract method 0x570ca735.
+//
+// Solidity: function operator() view returns(address)
+func (_Settlement *SettlementCallerSession) Operator() (common.Address, error) {
+	return _Settlement.Contract.Operator(&_Settlement.CallOpts)
+}
+
+// Pxa is a free data retrieval call binding the contract method 0xce509f8d.
+//
+// Solidity: function pxa() view returns(address)
+func (_Settlement *SettlementCaller) Pxa(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "pxa")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Pxa is a free data retrieval call binding the contract method 0xce509f8d.
+//
+// Solidity: function pxa() view returns(address)
+func (_Settlement *SettlementSession) Pxa() (common.Address, error) {
+	return _Settlement.Contract.Pxa(&_Settlement.CallOpts)
+}
+
+// Pxa is a free data retrieval call binding the contract method 0xce509f8d.
+//
+// Solidity: function pxa() view returns(address)
+func (_Settlement *SettlementCallerSession) Pxa() (common.Address, error) {
+	return _Settlement.Contract.Pxa(&_Settlement.CallOpts)
+}
+
+// Pxc is a free data retrieval call binding the contract method 0x5cd917f2.
+//
+// Solidity: function pxc() view returns(address)
+func (_Settlement *SettlementCaller) Pxc(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Settlement.contract.Call(opts, &out, "pxc")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Pxc is a free data retrieval call binding the contract method 0x5cd917f2.
+//
+// Solidity: function pxc() view returns(address)
+func (_Settlement *SettlementSession) Pxc() (common.Address, error) {
+	return _Settlement.Contract.Pxc(&_Settlement.CallOpts)
+}
+
+// Pxc is a free data retrieval call binding the contract method 0x5cd917f2.
+//
+// Solidity: function pxc() view returns(address)
+func (_Settlement *SettlementCallerSession) Pxc() (common.Address, error) {
+	return _Settlement.Contract.Pxc(&_Settlement.CallOpts)
+}
+
+// Settle is a paid mutator transaction binding the contract method 0x6cf9c681.
+//
+// Solidity: function settle(address buyer, address seller, uint256 tokenId, uint256 weight, uint256 price, address creator, uint256 royalty) returns()
+func (_Settlement *SettlementTransactor) Settle(opts *bind.TransactOpts, buyer common.Address, seller common.Address, tokenId *big.Int, weight *big.Int, price *big.Int, creator common.Address, royalty *big.Int) (*types.Transaction, error) {
+	return _Settlement.contract.Transact(opts, "settle", buyer, seller, tokenId, weight, price, creator, royalty)
+}
+
+// Settle is a paid mutator transaction binding the contract method 0x6cf9c681.
+//
+// Solidity: function settle(address buyer, address seller, uint256 tokenId, uint256 weight, uint256 price, address creator, uint256 royalty) returns()
+func (_Settlement *SettlementSession) Settle(buyer common.Address, seller common.Address, tokenId *big.Int, weight *big.Int, price *big.Int, creator common.Address, royalty *big.Int) (*types.Transaction, error) {
+	return _Settlement.Contract.Settle(&_Settlement.TransactOpts, buyer, seller, tokenId, weight, price, creator, royalty)
+}
+
+// Settle is a paid mutator transaction binding the contract method 0x6cf9c681.
+//
+// Solidity: function settle(address buyer, address seller, uint256 tokenId, uint256 weight, uint256 price, address creator, uint256 royalty) returns()
+func (_Settlement *SettlementTransactorSession) Settle(buyer common.Address, seller common.Address, tokenId *big.Int, weight *big.Int, price *big.Int, creator common.Address, royalty *big.Int) (*types.Transaction, error) {
+	return _Settlement.Contract.Settle(&_Settlement.TransactOpts, buyer, seller, tokenId, weight, price, creator, royalty)
+}
+
+// SettlementSettledIterator is returned from FilterSettled and is used to iterate over the raw logs and unpacked data for Settled events raised by the Settlement contract.
+type SettlementSettledIterator struct {
+	Event *SettlementSettled // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SettlementSettledIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SettlementSettled)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SettlementSettled)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SettlementSettledIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SettlementSettledIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SettlementSettled represents a Settled event raised by the Settlement contract.
+type SettlementSettled struct {
+	Buyer   common.Address
+	Seller  common.Address
+	TokenId *big.Int
+	Weight  *big.Int
+	Price   *big.Int
+	Creator common.Address
+	Royalty *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterSettled is a free log retrieval operation binding the contract event 0xf8ccc60cdc1745fe7f26bac50b420352095b56dcb5e219294aabe445e48e4276.
+//
+// Solidity: event Settled(address indexed buyer, address indexed seller, uint256 indexed tokenId, uint256 weight, uint256 price, address creator, uint256 royalty)
+func (_Settlement *SettlementFilterer) FilterSettled(opts *bind.FilterOpts, buyer []common.Address, seller []common.Address, tokenId []*big.Int) (*SettlementSettledIterator, error) {
+
+	var buyerRule []interface{}
+	for _, buyerItem := range buyer {
+		buyerRule = append(buyerRule, buyerItem)
+	}
+	var sellerRule []interface{}
+	for _, sellerItem := range seller {
+		sellerRule = append(sellerRule, sellerItem)
+	}
+	var tokenIdRule []interface{}
+	for _, tokenIdItem := range tokenId {
+		tokenIdRule = append(tokenIdRule, tokenIdItem)
+	}
+
+	logs, sub, err := _Settlement.contract.FilterLogs(opts, "Settled", buyerRule, sellerRule, tokenIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SettlementSettledIterator{contract: _Settlement.contract, event: "Settled", logs: logs, sub: sub}, nil
+}
+
+// WatchSettled is a free log subscription operation binding the contract event 0xf8ccc60cdc1745fe7f26bac50b420352095b56dcb5e219294aabe445e48e4276.
+//
+// Solidity: event Settled(address indexed buyer, address indexed seller, uint256 indexed tokenId, uint256 weight, uint256 price, address creator, uint256 royalty)
+func (_Settlement *SettlementFilterer) WatchSettled(opts *bind.WatchOpts, sink chan<- *SettlementSettled, buyer []common.Address, seller []common.Address, tokenId []*big.Int) (event.Subscription, error) {
+
+	var buyerRule []interface{}
+	for _, buyerItem := range buyer {
+		buyerRule = append(buyerRule, buyerItem)
+	}
+	var sellerRule []interface{}
+	for _, sellerItem := range seller {
+		sellerRule = append(sellerRule, sellerItem)
+	}
+	var tokenIdRule []interface{}
+	for _, tokenIdItem := range tokenId {
+		tokenIdRule = append(tokenIdRule, tokenIdItem)
+	}
+
+	logs, sub, err := _Settlement.contract.WatchLogs(opts, "Settled", buyerRule, sellerRule, tokenIdRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SettlementSettled)
+				if err := _Settlement.contract.UnpackLog(event, "Settled", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSettled is a log parse operation binding the contract event 0xf8ccc60cdc1745fe7f26bac50b420352095b56dcb5e219294aabe445e48e4276.
+//
+// Solidity: event Settled(address indexed buyer, address indexed seller, uint256 indexed tokenId, uint256 weight, uint256 price, address creator, uint256 royalty)
+func (_Settlement *SettlementFilterer) ParseSettled(log types.Log) (*SettlementSettled, error) {
+	event := new(SettlementSettled)
+	if err := _Settlement.contract.UnpackLog(event, "Settled", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
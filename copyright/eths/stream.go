@@ -0,0 +1,180 @@
+package eths
+
+import (
+	"context"
+	"copyright/dbs"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// 每个订阅者的事件缓冲区大小，消费过慢时丢弃新事件
+const STREAM_BUFFER = 64
+
+// 推送给浏览器的实时事件
+type TokenEvent struct {
+	ChainID string `json:"chain_id"` //事件所在链ID
+	Token   string `json:"token"`    //PXC20 或 PXA721
+	TokenHistory
+}
+
+// 某地址的事件订阅，事件流中断时C会被关闭
+type EventSubscription struct {
+	hub     *eventHub
+	address common.Address
+	C       chan TokenEvent
+}
+
+// 事件分发中心：每条链在整个进程中共用一组链上订阅，按地址分发给各订阅者
+type eventHub struct {
+	ch      *Chain
+	mu      sync.Mutex
+	running bool
+	subs    map[*EventSubscription]struct{}
+}
+
+// 订阅本链上与某地址相关的PXC20和PXA721事件
+func (ch *Chain) SubscribeEvents(address string) (*EventSubscription, error) {
+	h := ch.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.running {
+		if err := h.start(); err != nil {
+			return nil, err
+		}
+	}
+	sub := &EventSubscription{
+		hub:     h,
+		address: common.HexToAddress(address),
+		C:       make(chan TokenEvent, STREAM_BUFFER),
+	}
+	h.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// 取消订阅
+func (s *EventSubscription) Unsubscribe() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		close(s.C)
+	}
+}
+
+// 建立WebSocket连接并订阅合约事件，调用方需持有锁
+func (h *eventHub) start() error {
+	//1. 连接WebSocket RPC，事件订阅(Watch*)需要WebSocket连接
+	cli, err := ethclient.Dial(h.ch.wsURL)
+	if err != nil {
+		fmt.Println("Failed to dial websocket rpc", err)
+		return err
+	}
+	pxc, err := NewPxc20Filterer(common.HexToAddress(h.ch.pxcAddr), cli)
+	if err != nil {
+		fmt.Println("Failed to NewPxc20Filterer", err)
+		cli.Close()
+		return err
+	}
+	pxa, err := NewPxa721Filterer(common.HexToAddress(h.ch.pxaAddr), cli)
+	if err != nil {
+		fmt.Println("Failed to NewPxa721Filterer", err)
+		cli.Close()
+		return err
+	}
+	//2. 订阅事件
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := &bind.WatchOpts{Context: ctx}
+	pxcTransfer := make(chan *Pxc20Transfer)
+	pxcApproval := make(chan *Pxc20Approval)
+	pxaTransfer := make(chan *Pxa721Transfer)
+	pxaApproval := make(chan *Pxa721Approval)
+	pxaApprovalForAll := make(chan *Pxa721ApprovalForAll)
+	subs := []event.Subscription{}
+	stop := func() {
+		for _, s := range subs {
+			s.Unsubscribe()
+		}
+		cancel()
+		cli.Close()
+	}
+	watchers := []func() (event.Subscription, error){
+		func() (event.Subscription, error) { return pxc.WatchTransfer(opts, pxcTransfer, nil, nil) },
+		func() (event.Subscription, error) { return pxc.WatchApproval(opts, pxcApproval, nil, nil) },
+		func() (event.Subscription, error) { return pxa.WatchTransfer(opts, pxaTransfer, nil, nil, nil) },
+		func() (event.Subscription, error) { return pxa.WatchApproval(opts, pxaApproval, nil, nil, nil) },
+		func() (event.Subscription, error) { return pxa.WatchApprovalForAll(opts, pxaApprovalForAll, nil, nil) },
+	}
+	for _, watch := range watchers {
+		s, err := watch()
+		if err != nil {
+			fmt.Println("Failed to watch contract events", err)
+			stop()
+			return err
+		}
+		subs = append(subs, s)
+	}
+	// 任一订阅出错即整体重建
+	errc := make(chan error, len(subs))
+	for _, s := range subs {
+		go func(s event.Subscription) { errc <- <-s.Err() }(s)
+	}
+	h.running = true
+
+	//3. 分发事件
+	go func() {
+		for {
+			select {
+			case ev := <-pxcTransfer:
+				h.dispatch("PXC20", pxc20TransferEvent(ev))
+			case ev := <-pxcApproval:
+				h.dispatch("PXC20", pxc20ApprovalEvent(ev))
+			case ev := <-pxaTransfer:
+				h.dispatch("PXA721", pxa721TransferEvent(ev))
+			case ev := <-pxaApproval:
+				h.dispatch("PXA721", pxa721ApprovalEvent(ev))
+			case ev := <-pxaApprovalForAll:
+				h.dispatch("PXA721", pxa721ApprovalForAllEvent(ev))
+			case err := <-errc:
+				fmt.Println("event subscription terminated", err)
+				stop()
+				h.shutdown()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// 将事件发送给相关地址的订阅者
+func (h *eventHub) dispatch(token string, e dbs.ChainEvent) {
+	from := common.HexToAddress(e.From)
+	to := common.HexToAddress(e.To)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if sub.address != from && sub.address != to {
+			continue
+		}
+		select {
+		case sub.C <- TokenEvent{ChainID: h.ch.ChainID(), Token: token, TokenHistory: h.ch.toHistory(sub.address, e)}:
+		default:
+			fmt.Println("event stream buffer is full, drop event for", sub.address.Hex())
+		}
+	}
+}
+
+// 链上订阅中断时关闭所有订阅者，下次订阅时重新连接
+func (h *eventHub) shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		close(sub.C)
+	}
+	h.subs = map[*EventSubscription]struct{}{}
+	h.running = false
+}
@@ -134,6 +134,16 @@ func (ks HDkeyStore) SignTx(address common.Address, tx *types.Transaction, chain
 	return signedTx, nil
 }
 
+// 对消息哈希签名，返回以太坊格式的65字节签名（v为27或28）
+func (ks HDkeyStore) SignHash(hash []byte) ([]byte, error) {
+	sig, err := crypto.Sign(hash, ks.Key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
 func (ks HDkeyStore) NewTransactOpts(chainID *big.Int) (*bind.TransactOpts, error) {
 	return bind.NewKeyedTransactorWithChainID(ks.Key.PrivateKey, chainID)
 }
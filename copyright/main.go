@@ -1,7 +1,9 @@
 package main
 
 import (
+	"copyright/eths"
 	"copyright/routes"
+	"os"
 
 	"github.com/labstack/echo/v4/middleware"
 
@@ -9,19 +11,26 @@ import (
 )
 
 var Pecho *echo.Echo //echo框架对象全局定义
-// 静态文件处理
+// 内容文件处理，从配置的存储后端读取
 func staticFile() {
-	Pecho.Static("/contents", "static/contents")
+	Pecho.GET("/contents/:name", routes.ServeContent)
 }
 
 func main() {
+	// 连接配置的各条链
+	eths.Connect()
+	// 子命令：部署合约
+	if len(os.Args) > 1 && os.Args[1] == "deploy" {
+		deploy(os.Args[2:])
+		return
+	}
 	//创建echo对象
 	Pecho = echo.New()
 	//添加CORS中间件允许跨域请求
 	Pecho.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{echo.GET, echo.POST, echo.DELETE},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderCookie},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderCookie, routes.HeaderIdempotencyKey},
 		AllowCredentials: true,
 	}))
 	//安装日志中间件
@@ -31,11 +40,21 @@ func main() {
 	//在传输时使用压缩中间件
 	Pecho.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 5,
+		// 事件流需要逐条推送，不做压缩
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/events/stream"
+		},
 	}))
 
 	staticFile()
 
+	// 后台运行各条链的事件索引器
+	eths.RunIndexer()
+	// 后台清理过期的分片上传
+	routes.RunUploadCleaner()
+
 	Pecho.GET("/ping", routes.Ping)
+	Pecho.GET("/chains", routes.GetChains) //查询已连接的链
 	Pecho.POST("/register", routes.Register)
 	Pecho.POST("/login", routes.Login)
 	Pecho.GET("/session", routes.Session)
@@ -43,24 +62,55 @@ func main() {
 	Pecho.GET("/users", routes.ListUsers)
 	Pecho.DELETE("/users", routes.DeleteUser)
 
-	Pecho.POST("/content", routes.Upload)     // 上传图片
-	Pecho.GET("/content", routes.GetContents) //查看登录用户所有图片
-
-	Pecho.POST("/auction", routes.Auction)                  //卖家挂牌出售
-	Pecho.DELETE("/auction", routes.DeleteAuction)          //删除拍卖商品
-	Pecho.GET("/auctions", routes.GetAuctions)              //查看当前用户可买的商品列表
-	Pecho.GET("/myauctions", routes.GetMyAuctions)          //查看当前用户上架的拍卖列表
-	Pecho.POST("/auction/bid", routes.BidAuction)           //用户购买一个商品
-	Pecho.GET("/auction/history", routes.GetAuctionHistory) //查询用户拍卖历史记录（分页）
-	Pecho.GET("/pxa721/detail", routes.GetPXA721Detail)     //查询Token交易明细
-	Pecho.GET("/token/owner", routes.GetTokenOwner)         //查询Token所有者
-
-	Pecho.GET("/balance", routes.GetBalance) //获取以太坊余额
-	Pecho.POST("/transfer", routes.Transfer) //以太坊转账
-
-	Pecho.POST("/token/transfer", routes.TransferPXC)   //PXC代币转账
-	Pecho.POST("/token/mint", routes.MintToken)         //代币发放
-	Pecho.GET("/token/balance", routes.GetTokenBalance) //查询Token余额
-	Pecho.GET("/token/detail", routes.GetTokenDetail)   //查询Token交易明细
+	Pecho.POST("/content", routes.Upload, routes.Idempotency) // 上传图片
+	Pecho.GET("/content", routes.GetContents)                 //查看登录用户所有图片
+	Pecho.GET("/content/similar", routes.GetSimilarContents)  //检索相似内容
+	Pecho.POST("/content/uploads", routes.CreateUpload)       //创建分片上传
+	Pecho.GET("/content/uploads/:id", routes.GetUpload)       //查询分片上传进度
+	Pecho.POST("/content/uploads/:id", routes.UploadChunk)    //上传分片
+	Pecho.DELETE("/content/uploads/:id", routes.DeleteUpload) //取消分片上传
+
+	Pecho.GET("/content/:tokenId/verify", routes.GetVerification)     //核验版权登记信息
+	Pecho.GET("/content/:tokenId/certificate", routes.GetCertificate) //下载版权登记证书
+
+	Pecho.POST("/auction", routes.Auction)                                 //卖家挂牌出售
+	Pecho.DELETE("/auction", routes.DeleteAuction)                         //删除拍卖商品
+	Pecho.GET("/auctions", routes.GetAuctions)                             //查看当前用户可买的商品列表
+	Pecho.GET("/myauctions", routes.GetMyAuctions)                         //查看当前用户上架的拍卖列表
+	Pecho.POST("/auction/bid", routes.BidAuction, routes.Idempotency)      //用户购买一个商品
+	Pecho.GET("/auction/history", routes.GetAuctionHistory)                //查询用户拍卖历史记录（分页）
+	Pecho.GET("/royalty/history", routes.GetRoyaltyHistory)                //查询收到的版税记录（分页）
+	Pecho.GET("/eip712", routes.GetEIP712Schema)                           //查询挂牌和订单的EIP-712签名定义
+	Pecho.GET("/pxa721/detail", routes.GetPXA721Detail)                    //查询Token交易明细
+	Pecho.POST("/pxa721/transfer", routes.TransferPXA, routes.Idempotency) //整体转让版权NFT
+	Pecho.POST("/pxa721/operators", routes.AddOperator)                    //批量授权operator
+	Pecho.DELETE("/pxa721/operators", routes.RemoveOperator)               //取消批量授权
+	Pecho.GET("/pxa721/operators", routes.GetOperators)                    //查询批量授权状态
+	Pecho.GET("/token/owner", routes.GetTokenOwner)                        //查询Token所有者
+	Pecho.GET("/token/shares", routes.GetTokenShares)                      //查询持有人链上份额
+	Pecho.GET("/token/price", routes.GetTokenPrice)                        //查询最近成交单价
+	Pecho.GET("/metadata/:tokenId", routes.GetMetadata)                    //ERC-721元数据
+
+	Pecho.GET("/balance", routes.GetBalance)                     //获取以太坊余额
+	Pecho.POST("/transfer", routes.Transfer, routes.Idempotency) //以太坊转账
+
+	Pecho.POST("/token/transfer", routes.TransferPXC, routes.Idempotency)          //PXC代币转账
+	Pecho.POST("/token/mint", routes.MintToken, routes.Idempotency)                //代币发放
+	Pecho.GET("/token/balance", routes.GetTokenBalance)                            //查询Token余额
+	Pecho.POST("/token/approve", routes.ApprovePXC)                                //PXC授权
+	Pecho.GET("/token/allowance", routes.GetAllowance)                             //查询PXC授权额度
+	Pecho.POST("/token/transfer-from", routes.TransferFromPXC, routes.Idempotency) //PXC授权划转
+	Pecho.GET("/token/detail", routes.GetTokenDetail)                              //查询Token交易明细
+
+	Pecho.GET("/events/stream", routes.StreamEvents) //实时推送链上事件(SSE)
+
+	Pecho.GET("/admin/reconcile", routes.GetReconcile)     //股权对账报告
+	Pecho.POST("/admin/reconcile", routes.RepairReconcile) //按链上份额修复股权登记
+
+	Pecho.GET("/admin/moderation", routes.GetModerations)                                 //查询上传审核队列
+	Pecho.POST("/admin/moderation/approve", routes.ApproveModeration, routes.Idempotency) //审核通过并铸造
+	Pecho.POST("/admin/moderation/reject", routes.RejectModeration)                       //审核驳回
+
+	Pecho.POST("/admin/previews", routes.RegeneratePreviews) //为历史图片补生成缩略图和预览图
 	Pecho.Logger.Fatal(Pecho.Start(":9527"))
 }
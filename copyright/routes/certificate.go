@@ -0,0 +1,288 @@
+package routes
+
+import (
+	"copyright/configs"
+	"copyright/dbs"
+	"copyright/eths"
+	"copyright/utils"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 版权登记核验信息，数据库登记与链上铸造记录
+type Verification struct {
+	TokenID      string          `json:"token_id"`      //token ID
+	Title        string          `json:"title"`         //作品名称
+	ContentHash  string          `json:"content_hash"`  //内容哈希
+	CID          string          `json:"cid"`           //IPFS CID
+	MimeType     string          `json:"mime_type"`     //内容MIME类型
+	Creator      string          `json:"creator"`       //创作者地址
+	RegisteredAt string          `json:"registered_at"` //登记时间
+	ChainID      string          `json:"chain_id"`      //所在链ID
+	ChainName    string          `json:"chain_name"`    //所在链名称
+	Minted       bool            `json:"minted"`        //是否已查到铸造交易
+	MintTxHash   string          `json:"mint_tx_hash"`  //铸造交易哈希
+	MintBlock    uint64          `json:"mint_block"`    //铸造交易所在区块
+	MintStatus   string          `json:"mint_status"`   //铸造交易确认状态 pending/confirmed
+	Owner        string          `json:"owner"`         //链上当前所有者
+	Holders      []MetadataShare `json:"holders"`       //当前份额持有人，按份额从高到低
+}
+
+// 对外访问地址，优先取配置，为空时取请求的Host
+func publicURL(c echo.Context) string {
+	baseURL := configs.Conf.PublicURL
+	if baseURL == "" {
+		baseURL = c.Scheme() + "://" + c.Request().Host
+	}
+	return strings.TrimRight(baseURL, "/")
+}
+
+// 查询登记信息和链上铸造记录，found为false表示token未登记
+func queryVerification(tokenID string) (v *Verification, found bool, err error) {
+	//1. 登记信息
+	content := dbs.Content{}
+	if err = content.QueryByTokenID(tokenID); err != nil {
+		return nil, false, err
+	}
+	if content.TokenID == "" {
+		return nil, false, nil
+	}
+	ch, err := eths.GetChain(content.ChainID)
+	if err != nil {
+		fmt.Println("token is minted on a chain that is not connected", tokenID, err)
+		return nil, true, err
+	}
+	v = &Verification{
+		TokenID:      tokenID,
+		Title:        content.Title,
+		ContentHash:  content.ContentHash,
+		CID:          content.CID,
+		MimeType:     content.MimeType,
+		Creator:      content.Creator,
+		RegisteredAt: content.CreatedAt,
+		ChainID:      ch.ChainID(),
+		ChainName:    ch.Name,
+		Holders:      []MetadataShare{},
+	}
+	//2. 当前份额持有人
+	holders, err := dbs.QueryEquityHolders(content.ChainID, tokenID)
+	if err != nil {
+		return nil, true, err
+	}
+	for _, h := range holders {
+		if h.Weight > 0 {
+			v.Holders = append(v.Holders, MetadataShare{Address: h.Address, Weight: h.Weight})
+		}
+	}
+	sort.SliceStable(v.Holders, func(i, j int) bool {
+		return v.Holders[i].Weight > v.Holders[j].Weight
+	})
+	//3. 铸造交易和链上所有者，尚未铸造时留空
+	id, _ := new(big.Int).SetString(tokenID, 10)
+	mint, minted, err := ch.MintEvent(id)
+	if err != nil {
+		return nil, true, err
+	}
+	if minted {
+		v.Minted = true
+		v.MintTxHash = mint.TxHash
+		v.MintBlock = mint.BlockNumber
+		v.MintStatus = mint.Status
+		if owner, err := ch.GetTokenOwner(id); err == nil {
+			v.Owner = owner.Hex()
+		}
+	}
+	return v, true, nil
+}
+
+// 核验版权登记信息 GET /content/:tokenId/verify，证书二维码指向该地址
+func GetVerification(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2. 获取请求参数
+	tokenID := c.Param("tokenId")
+	if _, ok := new(big.Int).SetString(tokenID, 10); !ok {
+		fmt.Println("invalid tokenID format")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid tokenID format")
+	}
+	//3. 查询登记信息和链上记录
+	v, found, err := queryVerification(tokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if !found {
+		fmt.Println("token not found", tokenID)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("token not found")
+	}
+	resp.Data = v
+	return nil
+}
+
+// 下载版权登记证书 GET /content/:tokenId/certificate
+func GetCertificate(c echo.Context) error {
+	//1.获取请求参数
+	tokenID := c.Param("tokenId")
+	if _, ok := new(big.Int).SetString(tokenID, 10); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid token id"})
+	}
+	//2.查询登记信息和链上记录
+	v, found, err := queryVerification(tokenID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": utils.RecodeText(utils.RECODE_DBERR)})
+	}
+	if !found {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "token not found"})
+	}
+	//3.生成PDF
+	data, err := renderCertificate(v, publicURL(c)+"/content/"+tokenID+"/verify")
+	if err != nil {
+		fmt.Println("failed to render certificate", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": utils.RecodeText(utils.RECODE_SYSERR)})
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"certificate-%s.pdf\"", tokenID))
+	return c.Blob(http.StatusOK, "application/pdf", data)
+}
+
+// 证书版面参数（pt）
+const (
+	certMargin     = 60.0
+	certLabelWidth = 90.0
+	certBottom     = 70.0
+	certQRSide     = 120.0
+)
+
+// 绘制证书：标题、登记信息、份额持有人，最后是核验二维码
+func renderCertificate(v *Verification, verifyURL string) ([]byte, error) {
+	qr, err := utils.NewQRCode(verifyURL)
+	if err != nil {
+		return nil, err
+	}
+	pdf := utils.NewPDF()
+	width := utils.PDF_PAGE_WIDTH
+	drawCertBorder(pdf)
+	//1. 标题
+	y := utils.PDF_PAGE_HEIGHT - 110
+	title := "数字版权登记证书"
+	pdf.Text((width-pdf.TextWidth(26, title))/2, y, 26, title)
+	y -= 24
+	pdf.SetGray(0.4)
+	subtitle := "Copyright Registration Certificate"
+	pdf.Text((width-pdf.TextWidth(11, subtitle))/2, y, 11, subtitle)
+	pdf.SetGray(0)
+	y -= 18
+	pdf.Line(certMargin, y, width-certMargin, y, 0.8)
+	y -= 30
+	//2. 登记信息
+	mintTx, mintBlock := "尚未铸造", "-"
+	if v.Minted {
+		mintTx = v.MintTxHash
+		mintBlock = fmt.Sprintf("%d", v.MintBlock)
+		if v.MintStatus == dbs.STATUS_CONFIRMED {
+			mintBlock += "（已确认）"
+		} else {
+			mintBlock += "（待确认）"
+		}
+	}
+	fields := [][2]string{
+		{"作品名称", v.Title},
+		{"Token ID", v.TokenID},
+		{"内容哈希", v.ContentHash},
+		{"IPFS CID", v.CID},
+		{"作品类型", v.MimeType},
+		{"创作者", v.Creator},
+		{"登记时间", v.RegisteredAt},
+		{"所在链", fmt.Sprintf("%s（链ID %s）", v.ChainName, v.ChainID)},
+		{"铸造交易", mintTx},
+		{"铸造区块", mintBlock},
+		{"链上所有者", v.Owner},
+	}
+	valueWidth := width - certMargin*2 - certLabelWidth
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		pdf.SetGray(0.35)
+		pdf.Text(certMargin, y, 11, f[0])
+		pdf.SetGray(0)
+		for _, line := range pdf.SplitText(10, valueWidth, f[1]) {
+			pdf.Text(certMargin+certLabelWidth, y, 10, line)
+			y -= 15
+		}
+		y -= 7
+	}
+	//3. 份额持有人，超出页面时续页
+	y -= 10
+	holderHeader := func(title string) {
+		pdf.Text(certMargin, y, 13, title)
+		y -= 20
+		pdf.SetGray(0.35)
+		pdf.Text(certMargin, y, 10, "持有人地址")
+		pdf.Text(width-certMargin-60, y, 10, "份额")
+		pdf.SetGray(0)
+		y -= 6
+		pdf.Line(certMargin, y, width-certMargin, y, 0.5)
+		y -= 15
+	}
+	holderHeader("当前份额持有人")
+	for _, h := range v.Holders {
+		if y < certBottom {
+			pdf.AddPage()
+			drawCertBorder(pdf)
+			y = utils.PDF_PAGE_HEIGHT - 90
+			holderHeader("当前份额持有人（续）")
+		}
+		pdf.Text(certMargin, y, 10, h.Address)
+		pdf.Text(width-certMargin-60, y, 10, fmt.Sprintf("%d / 100", h.Weight))
+		y -= 16
+	}
+	//4. 核验二维码及说明，放不下时另起一页
+	y -= 20
+	if y-certQRSide < certBottom-20 {
+		pdf.AddPage()
+		drawCertBorder(pdf)
+		y = utils.PDF_PAGE_HEIGHT - 90
+	}
+	module := certQRSide / float64(qr.Size)
+	qrTop := y
+	for qy := 0; qy < qr.Size; qy++ {
+		for qx := 0; qx < qr.Size; qx++ {
+			if qr.Dark(qx, qy) {
+				pdf.FillRect(certMargin+float64(qx)*module, qrTop-float64(qy+1)*module, module, module)
+			}
+		}
+	}
+	textX := certMargin + certQRSide + 20
+	textWidth := width - certMargin - textX
+	y = qrTop - 14
+	pdf.Text(textX, y, 11, "扫描二维码或访问以下地址，核验登记信息和链上记录：")
+	y -= 18
+	for _, line := range pdf.SplitText(9, textWidth, verifyURL) {
+		pdf.Text(textX, y, 9, line)
+		y -= 13
+	}
+	y -= 10
+	pdf.SetGray(0.4)
+	pdf.Text(textX, y, 9, "签发时间："+time.Now().Format("2006-01-02 15:04:05"))
+	y -= 13
+	pdf.Text(textX, y, 9, "本证书依据平台登记记录和区块链铸造交易生成，以核验结果为准。")
+	pdf.SetGray(0)
+	return pdf.Bytes(), nil
+}
+
+// 页面双线边框
+func drawCertBorder(pdf *utils.PDF) {
+	pdf.StrokeRect(30, 30, utils.PDF_PAGE_WIDTH-60, utils.PDF_PAGE_HEIGHT-60, 1.5)
+	pdf.StrokeRect(36, 36, utils.PDF_PAGE_WIDTH-72, utils.PDF_PAGE_HEIGHT-72, 0.5)
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"copyright/dbs"
+	"copyright/eths"
+	"copyright/utils"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 链选择参数，值为链ID或config.json中配置的链名称，缺省为默认链
+const CHAIN_PARAM = "chain_id"
+
+// 按请求参数（查询参数或表单字段）选择链
+func requestChain(c echo.Context) (*eths.Chain, error) {
+	ch, err := eths.GetChain(c.FormValue(CHAIN_PARAM))
+	if err != nil {
+		fmt.Println("failed to select chain", err)
+		return nil, err
+	}
+	return ch, nil
+}
+
+// 按token铸造所在的链选择链，未登记的token按请求参数选择
+func tokenChain(c echo.Context, tokenID string) (*eths.Chain, error) {
+	content := dbs.Content{}
+	if err := content.QueryByTokenID(tokenID); err != nil {
+		return nil, err
+	}
+	if content.ChainID == "" {
+		return requestChain(c)
+	}
+	ch, err := eths.GetChain(content.ChainID)
+	if err != nil {
+		fmt.Println("token is minted on a chain that is not connected", tokenID, err)
+		return nil, err
+	}
+	return ch, nil
+}
+
+// 已连接的链
+type ChainInfo struct {
+	ChainID    string `json:"chain_id"`   //链ID
+	Name       string `json:"name"`       //链名称
+	Default    bool   `json:"default"`    //是否为默认链
+	PXC20      string `json:"pxc20"`      //ERC20合约地址
+	PXA721     string `json:"pxa721"`     //ERC721合约地址
+	Settlement string `json:"settlement"` //结算合约地址
+	Market     string `json:"market"`     //平台（管理员）地址
+}
+
+// 查询已连接的链 GET /chains
+func GetChains(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2. 组织响应数据
+	result := []ChainInfo{}
+	for _, ch := range eths.Chains() {
+		contracts := ch.Contracts()
+		result = append(result, ChainInfo{
+			ChainID:    ch.ChainID(),
+			Name:       ch.Name,
+			Default:    ch.IsDefault(),
+			PXC20:      contracts.PXC20,
+			PXA721:     contracts.PXA721,
+			Settlement: contracts.Settlement,
+			Market:     ch.MarketAddress(),
+		})
+	}
+	resp.Data = result
+	return nil
+}
@@ -0,0 +1,194 @@
+package routes
+
+import (
+	"bufio"
+	"copyright/configs"
+	"copyright/dbs"
+	"copyright/eths"
+	"copyright/storage"
+	"copyright/utils"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 派生图片的最长边（像素）
+const (
+	THUMB_SIZE   = 320
+	PREVIEW_SIZE = 1024
+)
+
+// 派生图片的文件名后缀，公开访问
+const (
+	THUMB_SUFFIX   = "_thumb.jpg"
+	PREVIEW_SUFFIX = "_preview.jpg"
+)
+
+// 读取存储后端中的内容 GET /contents/:name
+// 缩略图和预览图公开访问；原件仅限份额持有人和管理员（审核待定内容）访问
+// 本地存储支持Range请求，便于音视频拖动播放；Content-Type按扩展名确定，不做内容嗅探
+func ServeContent(c echo.Context) error {
+	name := path.Base(c.Param("name"))
+	//1. 原件访问控制
+	if !strings.HasSuffix(name, THUMB_SUFFIX) && !strings.HasSuffix(name, PREVIEW_SUFFIX) {
+		if err := checkOriginalAccess(c, name); err != nil {
+			return err
+		}
+	}
+	//2. 读取存储后端
+	rc, err := storage.Default().Open(name)
+	if err == storage.ErrNotExist {
+		return echo.ErrNotFound
+	}
+	if err != nil {
+		fmt.Println("failed to open content", name, err)
+		return echo.ErrInternalServerError
+	}
+	defer rc.Close()
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, utils.MIMEByExt(path.Ext(name)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if rs, ok := rc.(io.ReadSeeker); ok {
+		http.ServeContent(w, c.Request(), name, time.Time{}, rs)
+		return nil
+	}
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, rc)
+	return err
+}
+
+// 校验当前用户能否访问原件：已登记内容的份额持有人，或任一链的管理员
+func checkOriginalAccess(c echo.Context, name string) error {
+	address, err := sessionAddress(c)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+	for _, ch := range eths.Chains() {
+		if ch.IsAdmin(address) {
+			return nil
+		}
+	}
+	content := dbs.Content{}
+	found, err := content.QueryByContentPath("/contents/" + name)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	if !found {
+		// 未登记（审核中）的原件只有管理员可以访问
+		return echo.ErrForbidden
+	}
+	weight, err := dbs.QueryEquityWeight(address, content.TokenID)
+	if err != nil {
+		return echo.ErrInternalServerError
+	}
+	if weight <= 0 {
+		return echo.ErrForbidden
+	}
+	return nil
+}
+
+// 由原图生成缩略图和带token ID水印的预览图，保存到存储后端，返回对外访问路径
+func deriveImages(r io.Reader, tokenID string) (thumb, preview string, err error) {
+	//1. 解码原图，像素数超过上限的图片不解码
+	img, _, err := utils.DecodeImage(bufio.NewReader(r), configs.ImageMaxPixels())
+	if err != nil {
+		fmt.Println("failed to decode image", err)
+		return "", "", err
+	}
+	//2. 缩略图尺寸较小，不加水印
+	if thumb, err = saveDerived(utils.Resize(img, THUMB_SIZE), tokenID+THUMB_SUFFIX); err != nil {
+		return "", "", err
+	}
+	//3. 预览图平铺token ID水印
+	p := utils.Resize(img, PREVIEW_SIZE)
+	utils.Watermark(p, "#"+tokenID)
+	if preview, err = saveDerived(p, tokenID+PREVIEW_SUFFIX); err != nil {
+		return "", "", err
+	}
+	return thumb, preview, nil
+}
+
+// 编码为JPEG写入临时文件后保存到存储后端
+func saveDerived(img image.Image, name string) (string, error) {
+	tmp, err := os.CreateTemp(UPLOAD_TMP_DIR, "derived-*")
+	if err != nil {
+		fmt.Println("failed to create temp file", err)
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	err = jpeg.Encode(tmp, img, &jpeg.Options{Quality: 85})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Println("failed to encode jpeg", err)
+		return "", err
+	}
+	if err = storage.Default().Save(tmp.Name(), name, "image/jpeg"); err != nil {
+		fmt.Println("failed to save derived image", err)
+		return "", err
+	}
+	return "/contents/" + name, nil
+}
+
+// 为历史图片补生成缩略图和预览图 POST /admin/previews?token_id=
+func RegeneratePreviews(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//2. 管理员校验
+	if _, err := adminAddress(c, ch); err != nil {
+		fmt.Println("failed to check admin", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	//3. 查询尚未生成预览图的图片
+	contents, err := dbs.QueryMissingPreviews(ch.ChainID(), c.QueryParam("token_id"))
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	//4. 逐个生成，单个失败不影响其他内容
+	generated, failed := []string{}, []string{}
+	for i := range contents {
+		content := &contents[i]
+		if err := regeneratePreview(content); err != nil {
+			fmt.Println("failed to generate preview", content.TokenID, err)
+			failed = append(failed, content.TokenID)
+			continue
+		}
+		generated = append(generated, content.TokenID)
+	}
+	resp.Data = map[string]interface{}{
+		"generated": generated,
+		"failed":    failed,
+	}
+	return nil
+}
+
+func regeneratePreview(content *dbs.Content) error {
+	rc, err := storage.Default().Open(path.Base(content.ContentPath))
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	content.Thumbnail, content.Preview, err = deriveImages(rc, content.TokenID)
+	if err != nil {
+		return err
+	}
+	return content.UpdatePreviews()
+}
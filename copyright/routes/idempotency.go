@@ -0,0 +1,246 @@
+package routes
+
+import (
+	"bytes"
+	"copyright/dbs"
+	"copyright/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 幂等键请求头
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// 回放响应时附加的标记头
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+// 幂等键保留时间，超过后同一个键可以再次使用
+const IDEMPOTENCY_RETENTION = 24 * time.Hour
+
+// 幂等键最大长度
+const IDEMPOTENCY_KEY_MAX = 128
+
+// 非multipart请求体的大小上限，计算请求哈希时读入内存
+const IDEMPOTENCY_MAX_BODY = 1 << 20
+
+// 解析multipart表单时内存中保留的大小，与echo读取表单时一致，超过部分由标准库暂存到临时文件
+const IDEMPOTENCY_MULTIPART_MEMORY = 32 << 20
+
+// 上下文中标记处理函数已执行不可撤销操作的键
+const idempotencyIrreversible = "idempotency_irreversible"
+
+// 处理函数在发送链上交易等不可撤销的操作前调用：此后即使处理失败也不再释放幂等键，
+// 重试时回放失败响应，避免同一请求再次上链
+func markIrreversible(c echo.Context) {
+	c.Set(idempotencyIrreversible, true)
+}
+
+// 处理函数是否已执行不可撤销的操作
+func irreversible(c echo.Context) bool {
+	done, _ := c.Get(idempotencyIrreversible).(bool)
+	return done
+}
+
+// 幂等记录的存储，测试时替换为内存实现
+type idempotencyStore interface {
+	Reserve(i *dbs.Idempotency, retention time.Duration) (bool, error)
+	Query(i *dbs.Idempotency) error
+	Save(i *dbs.Idempotency) error
+	Release(i *dbs.Idempotency) error
+}
+
+// 基于t_idempotency表的幂等记录存储
+type dbIdempotencyStore struct{}
+
+func (dbIdempotencyStore) Reserve(i *dbs.Idempotency, retention time.Duration) (bool, error) {
+	return i.Reserve(retention)
+}
+
+func (dbIdempotencyStore) Query(i *dbs.Idempotency) error {
+	return i.Query()
+}
+
+func (dbIdempotencyStore) Save(i *dbs.Idempotency) error {
+	return i.Save()
+}
+
+func (dbIdempotencyStore) Release(i *dbs.Idempotency) error {
+	return i.Release()
+}
+
+// 当前使用的幂等记录存储
+var idempotencies idempotencyStore = dbIdempotencyStore{}
+
+// 记录响应内容的Writer
+type idempotencyWriter struct {
+	http.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// 幂等中间件：相同Idempotency-Key的重复请求直接回放首次响应，避免重复扣款
+func Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return next(c)
+		}
+		if len(key) > IDEMPOTENCY_KEY_MAX {
+			resp := utils.Resp{Errno: utils.RECODE_PARAMERR}
+			ResponseData(c, &resp)
+			return errors.New("idempotency key too long")
+		}
+		// 幂等键按登录用户隔离，未登录的请求不记录，避免不同调用方共用同一个空地址命名空间
+		address, err := sessionAddress(c)
+		if err != nil {
+			return next(c)
+		}
+		bodyHash, err := requestHash(c)
+		if err != nil {
+			resp := utils.Resp{Errno: utils.RECODE_PARAMERR}
+			ResponseData(c, &resp)
+			return err
+		}
+		record := &dbs.Idempotency{
+			Key:      key,
+			Address:  address,
+			Method:   c.Request().Method,
+			Path:     c.Path(),
+			BodyHash: bodyHash,
+		}
+		//1. 占用幂等键
+		reserved, err := idempotencies.Reserve(record, IDEMPOTENCY_RETENTION)
+		if err != nil {
+			resp := utils.Resp{Errno: utils.RECODE_DBERR}
+			ResponseData(c, &resp)
+			return err
+		}
+		//2. 已被占用则回放首次响应，请求内容不同说明键被误用
+		if !reserved {
+			if err = idempotencies.Query(record); err != nil {
+				resp := utils.Resp{Errno: utils.RECODE_DBERR}
+				ResponseData(c, &resp)
+				return err
+			}
+			if record.BodyHash != bodyHash {
+				fmt.Println("idempotency key reused with a different request:", key)
+				resp := utils.Resp{Errno: utils.RECODE_PARAMERR}
+				ResponseData(c, &resp)
+				return errors.New("idempotency key reused with a different request")
+			}
+			if record.Status == 0 {
+				fmt.Println("idempotent request is still in progress:", key)
+				resp := utils.Resp{Errno: utils.RECODE_INPROGRESSERR}
+				ResponseData(c, &resp)
+				return nil
+			}
+			c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+			return c.Blob(record.Status, echo.MIMEApplicationJSONCharsetUTF8, []byte(record.Response))
+		}
+		//3. 首次请求，执行处理函数并记录响应
+		res := c.Response()
+		writer := &idempotencyWriter{ResponseWriter: res.Writer, body: new(bytes.Buffer)}
+		res.Writer = writer
+		defer func() {
+			res.Writer = writer.ResponseWriter
+			// 尚未执行不可撤销操作时，未产生响应（如panic）或处理失败则释放幂等键，允许客户端使用同一个键重试
+			if !irreversible(c) && (!res.Committed || failedResponse(res.Status, writer.body.Bytes())) {
+				idempotencies.Release(record)
+				return
+			}
+			// 已上链但未产生响应时保持处理中状态，直到保留期结束都不允许重试
+			if !res.Committed {
+				fmt.Println("idempotent request aborted after an irreversible operation:", key)
+				return
+			}
+			// 成功响应，以及上链后才失败的响应，都按首次响应回放
+			record.Status = res.Status
+			record.Response = writer.body.String()
+			idempotencies.Save(record)
+		}()
+		return next(c)
+	}
+}
+
+// 响应是否表示处理失败：HTTP错误状态或错误码不为成功
+func failedResponse(status int, body []byte) bool {
+	if status >= http.StatusBadRequest {
+		return true
+	}
+	var resp utils.Resp
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false
+	}
+	return resp.Errno != utils.RECODE_OK
+}
+
+// 计算查询参数和请求体的哈希，并将请求体替换为可重复读取的副本供处理函数使用
+// multipart表单先按与处理函数相同的方式解析，按字段名和取值、文件字段名、文件名和大小计算，不读取文件内容，
+// 与分隔符无关，客户端重新编码表单后重试仍视为同一请求；其他请求体不得超过IDEMPOTENCY_MAX_BODY
+func requestHash(c echo.Context) (string, error) {
+	req := c.Request()
+	h := sha256.New()
+	io.WriteString(h, req.URL.RawQuery+"\n")
+	//1. multipart表单：解析结果由处理函数直接使用，文件只落盘一次
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if mediaType == echo.MIMEMultipartForm {
+		if err := req.ParseMultipartForm(IDEMPOTENCY_MULTIPART_MEMORY); err != nil {
+			fmt.Println("failed to parse multipart form", err)
+			return "", err
+		}
+		hashMultipart(h, req.MultipartForm)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	//2. 其他请求体读入内存，超过上限时拒绝
+	body, err := io.ReadAll(io.LimitReader(req.Body, IDEMPOTENCY_MAX_BODY+1))
+	if err != nil {
+		fmt.Println("failed to read request body", err)
+		return "", err
+	}
+	if len(body) > IDEMPOTENCY_MAX_BODY {
+		return "", errors.New("request body too large")
+	}
+	h.Write(body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// 按字段名排序，逐个写入普通字段的取值，以及文件字段的文件名和大小
+func hashMultipart(h hash.Hash, form *multipart.Form) {
+	names := make([]string, 0, len(form.Value))
+	for name := range form.Value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range form.Value[name] {
+			fmt.Fprintf(h, "%q %q\n", name, v)
+		}
+	}
+	names = names[:0]
+	for name := range form.File {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, f := range form.File[name] {
+			fmt.Fprintf(h, "%q %q %d\n", name, f.Filename, f.Size)
+		}
+	}
+}
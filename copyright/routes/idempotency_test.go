@@ -0,0 +1,330 @@
+package routes
+
+import (
+	"bytes"
+	"copyright/dbs"
+	"copyright/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 内存中的幂等记录存储
+type memIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]dbs.Idempotency
+}
+
+func newMemIdempotencyStore() *memIdempotencyStore {
+	return &memIdempotencyStore{records: map[string]dbs.Idempotency{}}
+}
+
+func (m *memIdempotencyStore) id(i *dbs.Idempotency) string {
+	return strings.Join([]string{i.Key, i.Address, i.Method, i.Path}, "\n")
+}
+
+func (m *memIdempotencyStore) Reserve(i *dbs.Idempotency, retention time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[m.id(i)]; ok {
+		return false, nil
+	}
+	m.records[m.id(i)] = *i
+	return true, nil
+}
+
+func (m *memIdempotencyStore) Query(i *dbs.Idempotency) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.records[m.id(i)]
+	if !ok {
+		return errors.New("not found")
+	}
+	i.BodyHash, i.Status, i.Response = r.BodyHash, r.Status, r.Response
+	return nil
+}
+
+func (m *memIdempotencyStore) Save(i *dbs.Idempotency) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[m.id(i)] = *i
+	return nil
+}
+
+func (m *memIdempotencyStore) Release(i *dbs.Idempotency) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.records[m.id(i)].Status == 0 {
+		delete(m.records, m.id(i))
+	}
+	return nil
+}
+
+// 测试用的处理函数：记录调用次数，按errno返回，irreversible为true时在返回前标记已上链
+type testHandler struct {
+	calls        int
+	errno        string
+	irreversible bool
+	abort        bool
+}
+
+func (h *testHandler) handle(c echo.Context) error {
+	h.calls++
+	if h.irreversible {
+		markIrreversible(c)
+	}
+	if h.abort {
+		return errors.New("aborted without response")
+	}
+	resp := utils.Resp{Errno: h.errno, Data: h.calls}
+	ResponseData(c, &resp)
+	return nil
+}
+
+// 使用内存存储执行一次经过幂等中间件的请求
+func serveIdempotent(t *testing.T, h *testHandler, cookie *http.Cookie, key, contentType string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	e.POST("/test", h.handle, Idempotency)
+	req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// 生成已登录用户的session cookie
+func loginCookie(t *testing.T, address string) *http.Cookie {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	sess, err := session.Get(req, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess.Values["address"] = address
+	if err = sess.Save(req, rec); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Result().Cookies()[0]
+}
+
+func useMemIdempotencyStore(t *testing.T) *memIdempotencyStore {
+	store := newMemIdempotencyStore()
+	old := idempotencies
+	idempotencies = store
+	t.Cleanup(func() { idempotencies = old })
+	return store
+}
+
+func respErrno(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp utils.Resp
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	return resp.Errno
+}
+
+func TestIdempotencyReplaysSuccess(t *testing.T) {
+	useMemIdempotencyStore(t)
+	cookie := loginCookie(t, "0x01")
+	h := &testHandler{errno: utils.RECODE_OK}
+	body := []byte(`{"to":"0x02","value":"1"}`)
+
+	first := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	second := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 1 {
+		t.Fatalf("handler called %d times, want 1", h.calls)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("replayed %q, want %q", second.Body.String(), first.Body.String())
+	}
+	if second.Header().Get(HeaderIdempotentReplayed) != "true" {
+		t.Errorf("replayed response is missing %s", HeaderIdempotentReplayed)
+	}
+	// 其他用户使用相同的键互不影响
+	serveIdempotent(t, h, loginCookie(t, "0x03"), "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 2 {
+		t.Errorf("handler called %d times for another user, want 2", h.calls)
+	}
+}
+
+func TestIdempotencyRejectsDifferentBody(t *testing.T) {
+	useMemIdempotencyStore(t)
+	cookie := loginCookie(t, "0x01")
+	h := &testHandler{errno: utils.RECODE_OK}
+
+	serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, []byte(`{"value":"1"}`))
+	rec := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, []byte(`{"value":"2"}`))
+	if h.calls != 1 {
+		t.Fatalf("handler called %d times, want 1", h.calls)
+	}
+	if errno := respErrno(t, rec); errno != utils.RECODE_PARAMERR {
+		t.Errorf("errno = %s, want %s", errno, utils.RECODE_PARAMERR)
+	}
+}
+
+func TestIdempotencyReleasesRetryableFailure(t *testing.T) {
+	useMemIdempotencyStore(t)
+	cookie := loginCookie(t, "0x01")
+	h := &testHandler{errno: utils.RECODE_DBERR}
+	body := []byte(`{"value":"1"}`)
+
+	serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	h.errno = utils.RECODE_OK
+	rec := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 2 {
+		t.Fatalf("handler called %d times, want 2", h.calls)
+	}
+	if errno := respErrno(t, rec); errno != utils.RECODE_OK {
+		t.Errorf("errno = %s, want %s", errno, utils.RECODE_OK)
+	}
+}
+
+func TestIdempotencyKeepsFailureAfterIrreversible(t *testing.T) {
+	useMemIdempotencyStore(t)
+	cookie := loginCookie(t, "0x01")
+	h := &testHandler{errno: utils.RECODE_DBERR, irreversible: true}
+	body := []byte(`{"value":"1"}`)
+
+	first := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	h.errno = utils.RECODE_OK
+	second := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 1 {
+		t.Fatalf("handler called %d times, want 1", h.calls)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("replayed %q, want %q", second.Body.String(), first.Body.String())
+	}
+}
+
+func TestIdempotencyKeepsAbortedIrreversible(t *testing.T) {
+	useMemIdempotencyStore(t)
+	cookie := loginCookie(t, "0x01")
+	h := &testHandler{irreversible: true, abort: true}
+	body := []byte(`{"value":"1"}`)
+
+	serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	h.abort = false
+	rec := serveIdempotent(t, h, cookie, "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 1 {
+		t.Fatalf("handler called %d times, want 1", h.calls)
+	}
+	if errno := respErrno(t, rec); errno != utils.RECODE_INPROGRESSERR {
+		t.Errorf("errno = %s, want %s", errno, utils.RECODE_INPROGRESSERR)
+	}
+}
+
+func TestIdempotencySkipsAnonymous(t *testing.T) {
+	store := useMemIdempotencyStore(t)
+	h := &testHandler{errno: utils.RECODE_OK}
+	body := []byte(`{"value":"1"}`)
+
+	serveIdempotent(t, h, nil, "k1", echo.MIMEApplicationJSON, body)
+	serveIdempotent(t, h, nil, "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 2 || len(store.records) != 0 {
+		t.Errorf("anonymous requests: calls = %d, records = %d, want 2 and 0", h.calls, len(store.records))
+	}
+}
+
+func TestIdempotencyRejectsLargeBody(t *testing.T) {
+	useMemIdempotencyStore(t)
+	h := &testHandler{errno: utils.RECODE_OK}
+	body := bytes.Repeat([]byte("a"), IDEMPOTENCY_MAX_BODY+1)
+
+	rec := serveIdempotent(t, h, loginCookie(t, "0x01"), "k1", echo.MIMEApplicationJSON, body)
+	if h.calls != 0 {
+		t.Fatalf("handler called %d times, want 0", h.calls)
+	}
+	if errno := respErrno(t, rec); errno != utils.RECODE_PARAMERR {
+		t.Errorf("errno = %s, want %s", errno, utils.RECODE_PARAMERR)
+	}
+}
+
+// 编码multipart表单，boundary为空时使用随机分隔符
+func multipartBody(t *testing.T, boundary string, fields map[string]string, fileName string, content []byte) (string, []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if boundary != "" {
+		if err := w.SetBoundary(boundary); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	fw, err := w.CreateFormFile("fileName", fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(content)
+	w.Close()
+	return w.FormDataContentType(), buf.Bytes()
+}
+
+func TestIdempotencyMultipartHash(t *testing.T) {
+	hashOf := func(contentType string, body []byte) string {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/content?chain=1", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		c := e.NewContext(req, httptest.NewRecorder())
+		h, err := requestHash(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 处理函数仍能读取解析后的表单
+		if _, err = c.FormFile("fileName"); err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	fields := map[string]string{"royalty_bps": "500"}
+	content := []byte("same size")
+	base := hashOf(multipartBody(t, "boundary1", fields, "a.png", content))
+
+	if h := hashOf(multipartBody(t, "boundary2", fields, "a.png", content)); h != base {
+		t.Error("hash depends on the multipart boundary")
+	}
+	if h := hashOf(multipartBody(t, "", map[string]string{"royalty_bps": "600"}, "a.png", content)); h == base {
+		t.Error("hash ignores form field values")
+	}
+	if h := hashOf(multipartBody(t, "", fields, "b.png", content)); h == base {
+		t.Error("hash ignores the file name")
+	}
+	if h := hashOf(multipartBody(t, "", fields, "a.png", append(content, '!'))); h == base {
+		t.Error("hash ignores the file size")
+	}
+}
+
+func TestIdempotencyReplaysUpload(t *testing.T) {
+	useMemIdempotencyStore(t)
+	cookie := loginCookie(t, "0x01")
+	h := &testHandler{errno: utils.RECODE_OK}
+	fields := map[string]string{"royalty_bps": "500"}
+
+	// 客户端重新编码表单后重试，回放首次响应
+	for i := 0; i < 2; i++ {
+		contentType, body := multipartBody(t, fmt.Sprint("boundary", i), fields, "a.png", []byte("png"))
+		serveIdempotent(t, h, cookie, "k1", contentType, body)
+	}
+	if h.calls != 1 {
+		t.Errorf("handler called %d times, want 1", h.calls)
+	}
+}
@@ -0,0 +1,258 @@
+package routes
+
+import (
+	"copyright/configs"
+	"copyright/dbs"
+	"copyright/eths"
+	"copyright/storage"
+	"copyright/utils"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 相似内容检索结果
+type SimilarContent struct {
+	dbs.Content
+	Similarity float64 `json:"similarity"` //感知哈希相似度（0~1）
+}
+
+// 登记内容并将全部100份额登记给上传用户
+func registerContent(content *dbs.Content) error {
+	if err := content.AddContent(); err != nil {
+		return err
+	}
+	equityRegistration := &dbs.EquityRegistration{
+		Address: content.Address,
+		TokenID: content.TokenID,
+		Weight:  100,
+	}
+	return equityRegistration.AddEquityRegistration()
+}
+
+// 检索与phash相似度不低于threshold的已登记内容，按相似度从高到低排列，exclude为排除的token
+func similarContents(phash string, threshold float64, exclude string) ([]SimilarContent, error) {
+	contents, err := dbs.QueryPHashes()
+	if err != nil {
+		return nil, err
+	}
+	result := []SimilarContent{}
+	for _, content := range contents {
+		if content.TokenID == exclude {
+			continue
+		}
+		similarity, err := utils.HashSimilarity(phash, content.PHash)
+		if err != nil {
+			fmt.Println("invalid phash of token", content.TokenID, err)
+			continue
+		}
+		if similarity >= threshold {
+			result = append(result, SimilarContent{Content: content, Similarity: similarity})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Similarity > result[j].Similarity
+	})
+	return result, nil
+}
+
+// 检索与某内容相似的已登记内容 GET /content/similar?token_id=&similarity=
+func GetSimilarContents(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2. 解析参数，相似度阈值缺省取配置
+	tokenID := c.QueryParam("token_id")
+	threshold := configs.Conf.Similarity
+	if str := c.QueryParam("similarity"); str != "" {
+		num, err := strconv.ParseFloat(str, 64)
+		if err != nil || num < 0 || num > 1 {
+			fmt.Println("invalid similarity", str)
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("invalid similarity")
+		}
+		threshold = num
+	}
+	content := dbs.Content{}
+	if err := content.QueryByTokenID(tokenID); err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if content.PHash == "" {
+		fmt.Println("content has no perceptual hash", tokenID)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("content has no perceptual hash")
+	}
+	//3. 检索相似内容
+	result, err := similarContents(content.PHash, threshold, tokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	resp.Data = result
+	return nil
+}
+
+// 查询审核队列 GET /admin/moderation?status=pending&pageNum=1&pageSize=10
+func GetModerations(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//2. 管理员校验
+	if _, err := adminAddress(c, ch); err != nil {
+		fmt.Println("failed to check admin", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	//3. 分页参数，状态缺省为待审核
+	status := c.QueryParam("status")
+	if status == "" {
+		status = dbs.MODERATION_PENDING
+	} else if status == "all" {
+		status = ""
+	}
+	pageNum := 1
+	pageSize := 10
+	if num, err := strconv.Atoi(c.QueryParam("pageNum")); err == nil && num > 0 {
+		pageNum = num
+	}
+	if num, err := strconv.Atoi(c.QueryParam("pageSize")); err == nil && num > 0 {
+		pageSize = num
+	}
+	//4. 查询数据库
+	pageResult, err := dbs.QueryModerations(ch.ChainID(), status, pageNum, pageSize)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	resp.Data = pageResult
+	return nil
+}
+
+// 审核通过：登记内容并由管理员铸造给上传用户 POST /admin/moderation/approve?id=
+func ApproveModeration(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2. 查询审核记录，按记录所在链校验管理员
+	m, ch, reviewer, err := reviewModeration(c, &resp)
+	if err != nil {
+		return err
+	}
+	//3. 抢占审核状态，防止重复铸造
+	if err = m.Review(dbs.MODERATION_APPROVED, reviewer); err != nil {
+		resp.Errno = utils.RECODE_REPEATERR
+		return err
+	}
+	//4. 铸造并等待交易打包，失败时恢复为待审核；上次铸造交易等待超时但已上链时不再重复铸造
+	content := m.Content
+	tokenid, _ := new(big.Int).SetString(content.TokenID, 10)
+	_, minted, err := ch.MintEvent(tokenid)
+	if err != nil {
+		m.Reopen()
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	if !minted {
+		markIrreversible(c)
+		if tx, err := ch.AdminUploadPic(content.Address, tokenid); err != nil {
+			m.Reopen()
+			if tx != nil {
+				resp.Data = map[string]string{"tx_hash": tx.Hash().Hex()}
+			}
+			resp.Errno = utils.RECODE_ETHERR
+			return err
+		}
+	}
+	//5. 铸造成功后登记内容和股权，重新审核时已登记的内容不重复登记
+	registered := dbs.Content{}
+	if err = registered.QueryByTokenID(content.TokenID); err != nil {
+		m.Reopen()
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if registered.TokenID == "" {
+		if err = registerContent(&content); err != nil {
+			m.Reopen()
+			resp.Errno = utils.RECODE_DBERR
+			return err
+		}
+	}
+	resp.Data = m
+	return nil
+}
+
+// 审核驳回：删除上传文件 POST /admin/moderation/reject?id=
+func RejectModeration(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2. 查询审核记录，按记录所在链校验管理员
+	m, _, reviewer, err := reviewModeration(c, &resp)
+	if err != nil {
+		return err
+	}
+	//3. 更新审核状态
+	if err = m.Review(dbs.MODERATION_REJECTED, reviewer); err != nil {
+		resp.Errno = utils.RECODE_REPEATERR
+		return err
+	}
+	//4. 删除上传文件及派生的缩略图和预览图
+	removeContentFiles(m.ContentPath, m.Thumbnail, m.Preview)
+	resp.Data = m
+	return nil
+}
+
+// 删除内容文件及派生的缩略图和预览图，删除失败只记录日志
+func removeContentFiles(paths ...string) {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if err := storage.Default().Delete(path.Base(p)); err != nil {
+			fmt.Println("failed to remove content file", p, err)
+		}
+	}
+}
+
+// 读取审核记录，并校验当前用户是该记录所在链的管理员
+func reviewModeration(c echo.Context, resp *utils.Resp) (*dbs.Moderation, *eths.Chain, string, error) {
+	id, err := strconv.ParseInt(c.FormValue("id"), 10, 64)
+	if err != nil {
+		fmt.Println("invalid moderation id", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return nil, nil, "", err
+	}
+	m := &dbs.Moderation{}
+	if err = m.QueryByID(id); err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return nil, nil, "", err
+	}
+	ch, err := eths.GetChain(m.ChainID)
+	if err != nil {
+		fmt.Println("moderation is on a chain that is not connected", m.ChainID, err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return nil, nil, "", err
+	}
+	reviewer, err := adminAddress(c, ch)
+	if err != nil {
+		fmt.Println("failed to check admin", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return nil, nil, "", err
+	}
+	return m, ch, reviewer, nil
+}
@@ -0,0 +1,29 @@
+package routes
+
+import "testing"
+
+func TestNormalizeNonce(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"16", "16", true},
+		{"0x10", "16", true},
+		{"0X10", "16", true},
+		{"016", "16", true},
+		{"0", "0", true},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", "115792089237316195423570985008687907853269984665640564039457584007913129639935", true},
+		{"", "", false},
+		{"-1", "", false},
+		{"1.5", "", false},
+		{"0xzz", "", false},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639936", "", false},
+	}
+	for _, c := range cases {
+		got, ok := normalizeNonce(c.in)
+		if ok != c.ok || got != c.want {
+			t.Errorf("normalizeNonce(%q) = %q, %v, want %q, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
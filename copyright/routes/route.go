@@ -1,16 +1,22 @@
 package routes
 
 import (
+	"copyright/configs"
 	"copyright/dbs"
 	"copyright/eths"
 	"copyright/utils"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
 )
@@ -91,8 +97,13 @@ func GetBalance(c echo.Context) error {
 		}
 		address = userAddress
 	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
 	// 调用balanceETH函数获取余额
-	balance, err := eths.BalanceETH(address)
+	balance, err := ch.BalanceETH(address)
 	if err != nil {
 		fmt.Println("Failed to get balance", err)
 		resp.Errno = utils.RECODE_ETHERR
@@ -151,8 +162,14 @@ func Transfer(c echo.Context) error {
 		resp.Errno = utils.RECODE_PARAMERR
 		return errors.New("invalid transfer value")
 	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
 	// 调用Transfer函数进行转账 - 直接传递*big.Int避免数值溢出
-	err = eths.Transfer(address, password, txData.To, valueBig)
+	markIrreversible(c)
+	err = ch.Transfer(address, password, txData.To, valueBig)
 	if err != nil {
 		fmt.Println("Failed to transfer ETH", err)
 		resp.Errno = utils.RECODE_ETHERR
@@ -204,15 +221,26 @@ func TransferPXC(c echo.Context) error {
 		resp.Errno = utils.RECODE_PARAMERR
 		return errors.New("invalid transfer parameters")
 	}
-	// 将string转换为*big.Int
-	valueBig, ok := new(big.Int).SetString(txData.Value, 10)
-	if !ok || valueBig.Cmp(big.NewInt(0)) <= 0 {
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	// 按代币精度将十进制金额转换为链上最小单位
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	valueBig, err := pxc.Parse(txData.Value)
+	if err != nil || valueBig.Sign() <= 0 {
 		fmt.Println("Invalid transfer value format or value is not positive")
 		resp.Errno = utils.RECODE_PARAMERR
 		return errors.New("invalid transfer value")
 	}
 	// 调用TransferPXC函数进行PXC代币转账
-	err = eths.TransferPXC(address, password, txData.To, valueBig)
+	markIrreversible(c)
+	tx, err := ch.TransferPXC(address, password, txData.To, valueBig)
 	if err != nil {
 		fmt.Println("Failed to transfer PXC", err)
 		resp.Errno = utils.RECODE_ETHERR
@@ -220,9 +248,230 @@ func TransferPXC(c echo.Context) error {
 	}
 	// 设置响应数据
 	resp.Data = map[string]interface{}{
-		"from":  address,
-		"to":    txData.To,
-		"value": txData.Value,
+		"from":    address,
+		"to":      txData.To,
+		"value":   pxc.Format(valueBig),
+		"symbol":  pxc.Symbol,
+		"tx_hash": tx.Hash().Hex(),
+	}
+	return nil
+}
+
+// PXC授权接口 POST /token/approve，spender为空时授权给平台地址
+func ApprovePXC(c echo.Context) error {
+	//组织响应消息
+	resp := utils.Resp{
+		Errno: "0",
+	}
+	defer ResponseData(c, &resp)
+	// 获取session中的用户信息
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("Failed to get session", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	address, ok := sess.Values["address"].(string)
+	if !ok || address == "" {
+		fmt.Println("Failed to get address from session")
+		resp.Errno = utils.RECODE_LOGINERR
+		return errors.New("please login first")
+	}
+	password, _ := sess.Values["password"].(string)
+	// 解析请求参数
+	req := struct {
+		Spender string `json:"spender"`
+		Value   string `json:"value"`
+	}{}
+	err = c.Bind(&req)
+	if err != nil {
+		fmt.Println("Failed to bind approve data", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	if req.Spender == "" {
+		req.Spender = ch.MarketAddress()
+	}
+	if !common.IsHexAddress(req.Spender) || req.Value == "" {
+		fmt.Println("Invalid approve parameters")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid approve parameters")
+	}
+	// 按代币精度将十进制金额转换为链上最小单位，额度为0表示撤销授权
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	valueBig, err := pxc.Parse(req.Value)
+	if err != nil {
+		fmt.Println("Invalid approve value format", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid approve value")
+	}
+	tx, err := ch.ApprovePXC(address, password, req.Spender, valueBig)
+	if err != nil {
+		fmt.Println("Failed to approve PXC", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	// 设置响应数据
+	resp.Data = map[string]interface{}{
+		"owner":   address,
+		"spender": req.Spender,
+		"value":   pxc.Format(valueBig),
+		"symbol":  pxc.Symbol,
+		"tx_hash": tx.Hash().Hex(),
+	}
+	return nil
+}
+
+// 查询PXC授权额度接口 GET /token/allowance?owner=0x...&spender=0x...
+func GetAllowance(c echo.Context) error {
+	//组织响应消息
+	resp := utils.Resp{
+		Errno: "0",
+	}
+	defer ResponseData(c, &resp)
+	// owner缺省为当前登录用户，spender缺省为平台地址
+	owner := c.QueryParam("owner")
+	if owner == "" {
+		sess, err := session.Get(c.Request(), "session")
+		if err != nil {
+			fmt.Println("Failed to get session", err)
+			resp.Errno = utils.RECODE_LOGINERR
+			return err
+		}
+		userAddress, ok := sess.Values["address"].(string)
+		if !ok || userAddress == "" {
+			fmt.Println("Failed to get address from session")
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("owner parameter is required")
+		}
+		owner = userAddress
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	spender := c.QueryParam("spender")
+	if spender == "" {
+		spender = ch.MarketAddress()
+	}
+	if !common.IsHexAddress(owner) || !common.IsHexAddress(spender) {
+		fmt.Println("Invalid allowance parameters")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid address")
+	}
+	allowance, err := ch.AllowancePXC(owner, spender)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	// 设置响应数据
+	resp.Data = map[string]interface{}{
+		"owner":     owner,
+		"spender":   spender,
+		"allowance": pxc.Format(allowance),
+		"symbol":    pxc.Symbol,
+	}
+	return nil
+}
+
+// PXC授权划转接口 POST /token/transfer-from，当前用户作为spender从owner账户划转给to
+func TransferFromPXC(c echo.Context) error {
+	//组织响应消息
+	resp := utils.Resp{
+		Errno: "0",
+	}
+	defer ResponseData(c, &resp)
+	// 获取session中的用户信息
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("Failed to get session", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	address, ok := sess.Values["address"].(string)
+	if !ok || address == "" {
+		fmt.Println("Failed to get address from session")
+		resp.Errno = utils.RECODE_LOGINERR
+		return errors.New("please login first")
+	}
+	password, _ := sess.Values["password"].(string)
+	// 解析请求参数
+	req := struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Value string `json:"value"`
+	}{}
+	err = c.Bind(&req)
+	if err != nil {
+		fmt.Println("Failed to bind transfer-from data", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	if req.To == "" {
+		req.To = address
+	}
+	if !common.IsHexAddress(req.From) || !common.IsHexAddress(req.To) || req.Value == "" {
+		fmt.Println("Invalid transfer-from parameters")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid transfer-from parameters")
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	valueBig, err := pxc.Parse(req.Value)
+	if err != nil || valueBig.Sign() <= 0 {
+		fmt.Println("Invalid transfer value format or value is not positive")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid transfer value")
+	}
+	// 先检查授权额度，避免发送必然失败的交易
+	allowance, err := ch.AllowancePXC(req.From, address)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	if allowance.Cmp(valueBig) < 0 {
+		fmt.Println("insufficient allowance", allowance, valueBig)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("insufficient allowance")
+	}
+	markIrreversible(c)
+	tx, err := ch.TransferFromPXC(address, password, req.From, req.To, valueBig)
+	if err != nil {
+		fmt.Println("Failed to transfer-from PXC", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	// 设置响应数据
+	resp.Data = map[string]interface{}{
+		"spender": address,
+		"from":    req.From,
+		"to":      req.To,
+		"value":   pxc.Format(valueBig),
+		"symbol":  pxc.Symbol,
+		"tx_hash": tx.Hash().Hex(),
 	}
 	return nil
 }
@@ -252,22 +501,34 @@ func GetTokenBalance(c echo.Context) error {
 		}
 		address = userAddress
 	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
 	// 调用Tokenbalance函数获取Token余额
-	balance, err := eths.Tokenbalance(address)
+	balance, err := ch.Tokenbalance(address)
 	if err != nil {
 		fmt.Println("Failed to get token balance", err)
 		resp.Errno = utils.RECODE_ETHERR
 		return err
 	}
-	// 设置响应数据
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	// 设置响应数据，余额按代币精度换算为十进制字符串
 	resp.Data = map[string]interface{}{
-		"address": address,
-		"balance": balance,
+		"address":  address,
+		"balance":  pxc.Format(balance),
+		"symbol":   pxc.Symbol,
+		"decimals": pxc.Decimals,
 	}
 	return nil
 }
 
-// 查询Token交易明细接口 GET /token/detail?address=0x...
+// 查询Token交易明细接口 GET /token/detail?address=0x...&event=Transfer&fromBlock=&toBlock=&pageNum=1&pageSize=10
 func GetTokenDetail(c echo.Context) error {
 	//组织响应消息
 	resp := utils.Resp{
@@ -292,21 +553,31 @@ func GetTokenDetail(c echo.Context) error {
 		}
 		address = userAddress
 	}
+	// 解析过滤和分页参数
+	q, pageNum, pageSize, err := parseHistoryQuery(c, eths.PXC20Events)
+	if err != nil {
+		fmt.Println("Invalid history query", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
 	// 调用Tokendetail函数获取Token交易明细
-	err := eths.Tokendetail(address)
+	pageResult, err := ch.Tokendetail(address, q, pageNum, pageSize)
 	if err != nil {
 		fmt.Println("Failed to get token detail", err)
-		resp.Errno = utils.RECODE_ETHERR
+		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
 	// 设置响应数据
-	resp.Data = map[string]interface{}{
-		"address": address,
-		"message": "token transaction details have been printed to console",
-	}
+	resp.Data = pageResult
 	return nil
 }
 
+// 查询PXA721交易明细接口 GET /pxa721/detail?address=0x...&event=Transfer&fromBlock=&toBlock=&pageNum=1&pageSize=10
 func GetPXA721Detail(c echo.Context) error {
 	//组织响应消息
 	resp := utils.Resp{
@@ -331,21 +602,64 @@ func GetPXA721Detail(c echo.Context) error {
 		}
 		address = userAddress
 	}
-
-	err := eths.PXA721detail(address)
+	// 解析过滤和分页参数
+	q, pageNum, pageSize, err := parseHistoryQuery(c, eths.PXA721Events)
+	if err != nil {
+		fmt.Println("Invalid history query", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	pageResult, err := ch.PXA721detail(address, q, pageNum, pageSize)
 	if err != nil {
 		fmt.Println("Failed to get token detail", err)
-		resp.Errno = utils.RECODE_ETHERR
+		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
 	// 设置响应数据
-	resp.Data = map[string]interface{}{
-		"address": address,
-		"message": "token transaction details have been printed to console",
-	}
+	resp.Data = pageResult
 	return nil
 }
 
+// 解析交易明细查询参数 event、fromBlock、toBlock、pageNum、pageSize
+func parseHistoryQuery(c echo.Context, events []string) (eths.HistoryQuery, int, int, error) {
+	q := eths.HistoryQuery{}
+	// 事件类型过滤
+	q.Event = c.QueryParam("event")
+	if q.Event != "" && !slices.Contains(events, q.Event) {
+		return q, 0, 0, fmt.Errorf("unsupported event type: %s", q.Event)
+	}
+	// 区块范围过滤
+	var err error
+	if v := c.QueryParam("fromBlock"); v != "" {
+		if q.FromBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return q, 0, 0, err
+		}
+	}
+	if v := c.QueryParam("toBlock"); v != "" {
+		if q.ToBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return q, 0, 0, err
+		}
+	}
+	if q.ToBlock > 0 && q.FromBlock > q.ToBlock {
+		return q, 0, 0, errors.New("fromBlock is greater than toBlock")
+	}
+	// 分页参数，默认值
+	pageNum := 1
+	pageSize := 10
+	if num, err := strconv.Atoi(c.QueryParam("pageNum")); err == nil && num > 0 {
+		pageNum = num
+	}
+	if num, err := strconv.Atoi(c.QueryParam("pageSize")); err == nil && num > 0 {
+		pageSize = num
+	}
+	return q, pageNum, pageSize, nil
+}
+
 // 登陆 POST /login {username,identity_id}
 func Login(c echo.Context) error {
 	//组织响应消息
@@ -466,6 +780,9 @@ func DeleteUser(c echo.Context) error {
 	return nil
 }
 
+// 版税上限（万分比），即成交金额的50%
+const ROYALTY_BPS_MAX = 5000
+
 // 上传图片功能
 // upload POST: /content
 func Upload(c echo.Context) error {
@@ -474,48 +791,8 @@ func Upload(c echo.Context) error {
 	resp.Errno = utils.RECODE_OK
 	defer ResponseData(c, &resp)
 
-	// 2.1解析数据
+	//2. 从session获取账户地址
 	content := &dbs.Content{}
-
-	h, err := c.FormFile("fileName")
-	if err != nil {
-		fmt.Println("failed to FormFile ", err)
-		resp.Errno = utils.RECODE_PARAMERR
-		return err
-	}
-	src, err := h.Open()
-	if err != nil {
-		fmt.Println("failed to open file ", err)
-		resp.Errno = utils.RECODE_SYSERR
-		return err
-	}
-	defer src.Close()
-	// 2.2 获得tokenid
-	tokenid := utils.NewTokenID()
-	content.TokenID = fmt.Sprintf("%d", tokenid)
-	filename := fmt.Sprintf("static/contents/%s.jpg", content.TokenID)
-	content.ContentPath = fmt.Sprintf("/contents/%s.jpg", content.TokenID)
-	dst, err := os.Create(filename)
-	if err != nil {
-		fmt.Println("failed to create file ", err, content.ContentPath)
-		resp.Errno = utils.RECODE_SYSERR
-		return err
-	}
-	defer dst.Close()
-	// 2.3 计算hash
-	cData := make([]byte, h.Size)
-	n, err := src.Read(cData)
-	if err != nil || h.Size != int64(n) {
-		resp.Errno = utils.RECODE_SYSERR
-		return err
-	}
-	hash := eths.KeccakHash(cData)
-	content.ContentHash = fmt.Sprintf("%x", hash)
-
-	dst.Write(cData)
-	content.Title = h.Filename
-
-	//3. 从session获取账户地址
 	sess, _ := session.Get(c.Request(), "session")
 	content.Address, _ = sess.Values["address"].(string)
 
@@ -525,30 +802,162 @@ func Upload(c echo.Context) error {
 		return errors.New("no session")
 	}
 
-	//4. 操作mysql-新增数据
-	err = content.AddContent()
+	//3. 取得上传文件：表单文件流式写入临时文件，或使用已完成的分片上传（upload_id）
+	var f *stagedFile
+	var upload *dbs.Upload
+	if uploadID := c.FormValue("upload_id"); uploadID != "" {
+		upload = &dbs.Upload{}
+		found, err := upload.QueryUpload(uploadID, content.Address)
+		if err != nil {
+			resp.Errno = utils.RECODE_DBERR
+			return err
+		}
+		if !found {
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("upload not found")
+		}
+		if f, err = stageChunkedUpload(upload, &resp); err != nil {
+			return err
+		}
+	} else {
+		h, err := c.FormFile("fileName")
+		if err != nil {
+			fmt.Println("failed to FormFile ", err)
+			resp.Errno = utils.RECODE_PARAMERR
+			return err
+		}
+		if f, err = stageFormFile(h, &resp); err != nil {
+			return err
+		}
+		// 登记失败时删除临时文件，分片上传保留以便重试
+		defer f.discard()
+	}
+	content.Title = f.title
+	content.MimeType = f.mimeType
+	content.ContentHash = f.hash
+	content.CID = f.cid
+	content.PHash = f.phash
+
+	// 3.1 铸造所在的链，缺省为默认链
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	content.ChainID = ch.ChainID()
+	// 3.2 相同内容只能登记一次，管理员可通过override=true重新登记
+	existing := dbs.Content{}
+	found, err := existing.QueryByContentHash(content.ContentHash)
 	if err != nil {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
-	// 登记股权
-	equityRegistration := &dbs.EquityRegistration{
-		Address: content.Address,
-		TokenID: content.TokenID,
-		Weight:  100,
+	if found {
+		if c.FormValue("override") != "true" || !ch.IsAdmin(content.Address) {
+			fmt.Println("duplicate content", content.ContentHash, "registered as token", existing.TokenID)
+			resp.Errno = utils.RECODE_DUPLICATEERR
+			resp.Data = map[string]string{
+				"token_id": existing.TokenID,
+				"owner":    existing.Address,
+				"creator":  existing.Creator,
+				"chain_id": existing.ChainID,
+			}
+			return errors.New("duplicate content")
+		}
+		fmt.Println("admin re-registers duplicate content", content.ContentHash, "of token", existing.TokenID)
 	}
-	err = equityRegistration.AddEquityRegistration()
+	// 相同内容已在审核队列中等待审核时同样视为重复
+	pending := dbs.Moderation{}
+	found, err = pending.QueryPendingByContentHash(content.ContentHash)
 	if err != nil {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
+	if found && (c.FormValue("override") != "true" || !ch.IsAdmin(content.Address)) {
+		fmt.Println("duplicate content", content.ContentHash, "pending moderation", pending.ID)
+		resp.Errno = utils.RECODE_DUPLICATEERR
+		resp.Data = map[string]interface{}{
+			"moderation_id": pending.ID,
+			"token_id":      pending.TokenID,
+			"owner":         pending.Address,
+			"chain_id":      pending.ChainID,
+			"status":        pending.Status,
+		}
+		return errors.New("duplicate content pending moderation")
+	}
+	// 3.3 二次销售版税（万分比），缺省为0
+	if royalty := c.FormValue("royalty_bps"); royalty != "" {
+		bps, err := strconv.ParseInt(royalty, 10, 64)
+		if err != nil || bps < 0 || bps > ROYALTY_BPS_MAX {
+			fmt.Println("invalid royalty_bps", royalty)
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("invalid royalty_bps")
+		}
+		content.RoyaltyBps = bps
+	}
+	// 3.4 获得tokenid，哈希确定后保留与类型一致的原始扩展名移入内容目录
+	tokenid := utils.NewTokenID()
+	content.TokenID = fmt.Sprintf("%d", tokenid)
+	content.FileExt = utils.FileExt(content.Title, content.MimeType)
+	if f.kind == configs.UPLOAD_IMAGE {
+		// 图片生成公开的缩略图和带水印预览图，原件仅限份额持有人访问
+		if content.Thumbnail, content.Preview, err = f.derive(content.TokenID); err != nil {
+			resp.Errno = utils.RECODE_SYSERR
+			return err
+		}
+	}
+	content.ContentPath, err = f.commit(content.TokenID + content.FileExt)
+	if err != nil {
+		resp.Errno = utils.RECODE_SYSERR
+		return err
+	}
+	if upload != nil {
+		if err = upload.DeleteUpload(); err != nil {
+			resp.Errno = utils.RECODE_DBERR
+			return err
+		}
+		uploadLocks.Delete(upload.UploadID)
+	}
+	// 3.5 与已登记内容过于相似的上传进入人工审核队列，审核通过后再登记和铸造，管理员上传不受限制
+	if content.PHash != "" && !ch.IsAdmin(content.Address) {
+		similar, err := similarContents(content.PHash, configs.Conf.Similarity, "")
+		if err != nil {
+			resp.Errno = utils.RECODE_DBERR
+			return err
+		}
+		if len(similar) > 0 {
+			m := &dbs.Moderation{
+				Content:        *content,
+				SimilarTokenID: similar[0].TokenID,
+				Similarity:     similar[0].Similarity,
+			}
+			if err = m.AddModeration(); err != nil {
+				resp.Errno = utils.RECODE_DBERR
+				return err
+			}
+			fmt.Println("upload flagged for moderation", m.ID, "similar to token", m.SimilarTokenID, m.Similarity)
+			resp.Data = m
+			return nil
+		}
+	}
 
-	//5. 操作以太坊
-	err = eths.UploadPic(content.Address, pass, content.Address, big.NewInt(tokenid))
+	//4. 操作以太坊，先铸造，提交失败时删除已保存的文件，重新上传不会被判为重复
+	markIrreversible(c)
+	err = ch.UploadPic(content.Address, pass, content.Address, big.NewInt(tokenid))
 	if err != nil {
+		removeContentFiles(content.ContentPath, content.Thumbnail, content.Preview)
 		resp.Errno = utils.RECODE_ETHERR
 		return err
 	}
+
+	//5. 操作mysql-新增数据，登记股权
+	if err = registerContent(content); err != nil {
+		// 铸造交易已提交，保留文件，按token和内容哈希人工补登
+		fmt.Println("token", content.TokenID, "minted but failed to register content", content.ContentHash)
+		resp.Errno = utils.RECODE_DBERR
+		resp.Data = map[string]string{"token_id": content.TokenID}
+		return err
+	}
 	return nil
 }
 
@@ -633,17 +1042,80 @@ func Auction(c echo.Context) error {
 		resp.Errno = utils.RECODE_REPEATERR
 		return err
 	}
+	ch, err := tokenChain(c, auction.TokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	// EIP-712挂牌签名：客户端钱包已签名时校验签名，否则使用卖家keystore在服务端签名
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	// 单价为十进制PXC金额，按代币精度换算为最小单位后签名和保存
+	price, err := pxc.Parse(auction.Price)
+	if err != nil || price.Sign() <= 0 {
+		fmt.Println("invalid listing price", auction.Price)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid listing price")
+	}
+	auction.Price = price.String()
+	if auction.Signature == "" {
+		auction.Nonce = strconv.FormatInt(time.Now().UnixNano(), 10)
+	} else if auction.Nonce, ok = normalizeNonce(auction.Nonce); !ok {
+		fmt.Println("invalid listing nonce")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid listing nonce")
+	}
+	listing := eths.Listing{
+		Seller:  addr,
+		TokenID: auction.TokenID,
+		Weight:  int64(auction.Weight),
+		Price:   price,
+		Nonce:   auction.Nonce,
+	}
+	if auction.Signature == "" {
+		auction.Signature, err = eths.SignTypedData(addr, pass, listing.TypedData(ch))
+		if err != nil {
+			resp.Errno = utils.RECODE_ETHERR
+			return err
+		}
+	} else if err = eths.VerifyTypedData(listing.TypedData(ch), auction.Signature, addr); err != nil {
+		fmt.Println("invalid listing signature", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	// 同一卖家的挂牌nonce只能使用一次，防止签名被重放
+	consumed, err := dbs.ConsumeNonce(ch.ChainID(), addr, dbs.NONCE_LISTING, auction.Nonce)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if !consumed {
+		fmt.Println("listing nonce already used", addr, auction.Nonce)
+		resp.Errno = utils.RECODE_REPEATERR
+		return errors.New("listing nonce already used")
+	}
 	// 挂牌出售
-	auction.Add()
+	err = auction.Add()
 	if err != nil {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
 
-	//5. 操作eth
+	//5. 操作eth，已批量授权给平台时无需逐个授权
+	approved, err := ch.IsApprovedForAll(auction.Address, ch.MarketAddress())
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	if approved {
+		return nil
+	}
 	value := big.NewInt(0)
 	value, _ = value.SetString(auction.TokenID, 10)
-	err = eths.SetApprove(auction.Address, pass, value)
+	err = ch.SetApprove(auction.Address, pass, value)
 	if err != nil {
 		resp.Errno = utils.RECODE_ETHERR
 		return err
@@ -651,6 +1123,39 @@ func Auction(c echo.Context) error {
 	return nil
 }
 
+// 规范化客户端提交的签名nonce（十进制或0x十六进制）为十进制字符串，与签名中的uint256取值一一对应
+func normalizeNonce(nonce string) (string, bool) {
+	if nonce == "" {
+		return "", false
+	}
+	// 负数不是合法的uint256，拒绝以免与其补码表示的取值重复登记
+	n, ok := math.ParseBig256(nonce)
+	if !ok || n.Sign() < 0 {
+		return "", false
+	}
+	return n.String(), true
+}
+
+// 查询EIP-712签名域和类型定义 GET /eip712，客户端钱包据此对挂牌和订单签名
+func GetEIP712Schema(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//2. 组织响应数据
+	domain, types := ch.EIP712Schema()
+	resp.Data = map[string]interface{}{
+		"domain": domain,
+		"types":  types,
+	}
+	return nil
+}
+
 // 查看当前用户可买的商品列表
 func GetAuctions(c echo.Context) error {
 	//1. 响应数据结构初始化
@@ -677,6 +1182,10 @@ func GetAuctions(c echo.Context) error {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
+	if err = formatListingPrices(c, auctions); err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
 
 	resp.Data = auctions
 
@@ -711,59 +1220,168 @@ func BidAuction(c echo.Context) error {
 	}
 	ah.Buyer = address
 
-	//4. 数据库操作
-	err = ah.Add()
+	ch, err := tokenChain(c, ah.TokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//3.1 校验卖家挂牌签名，防止挂牌数据被篡改
+	listed := dbs.Auction{TokenID: ah.TokenID, Address: ah.Address}
+	found, err := listed.QueryListing()
 	if err != nil {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
-	//4. 数据库操作-卖家份额扣减
-	auction := dbs.Auction{
-		TokenID: ah.TokenID,
-		Address: ah.Address,
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	// 出价单价为十进制PXC金额，换算为最小单位后须与挂牌单价一致
+	listedPrice, _ := new(big.Int).SetString(listed.Price, 10)
+	price, err := pxc.Parse(ah.Price)
+	if !found || ah.Weight <= 0 || ah.Weight > int64(listed.Weight) || err != nil || listedPrice == nil || price.Cmp(listedPrice) != 0 {
+		fmt.Println("bid does not match listing", ah.TokenID, ah.Address)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("bid does not match listing")
+	}
+	ah.Price = listedPrice.String()
+	listing := eths.Listing{
+		Seller:  listed.Address,
+		TokenID: listed.TokenID,
+		Weight:  int64(listed.ListedWeight),
+		Price:   listedPrice,
+		Nonce:   listed.Nonce,
+	}
+	if err = eths.VerifyTypedData(listing.TypedData(ch), listed.Signature, listed.Address); err != nil {
+		fmt.Println("invalid listing signature", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
 	}
-	err = auction.UpdateWeight(ah.Weight)
+	//3.2 买家须事先授权结算合约划转不少于本次总价的PXC
+	total := new(big.Int).Mul(big.NewInt(ah.Weight), price)
+	allowance, err := ch.AllowancePXC(address, ch.SettlementAddress())
 	if err != nil {
-		resp.Errno = utils.RECODE_DBERR
+		resp.Errno = utils.RECODE_ETHERR
 		return err
 	}
-	//4. 数据库操作-卖家库存扣减
-	equityRegistration := dbs.EquityRegistration{
+	if allowance.Cmp(total) < 0 {
+		fmt.Println("settlement allowance is insufficient", address, allowance, total)
+		resp.Errno = utils.RECODE_PARAMERR
+		resp.Data = map[string]interface{}{
+			"spender":   ch.SettlementAddress(),
+			"allowance": pxc.Format(allowance),
+			"required":  pxc.Format(total),
+		}
+		return eths.ErrAllowanceInsufficient
+	}
+	//3.3 买家订单签名：客户端钱包已签名时校验签名，否则使用买家keystore在服务端签名
+	if ah.Signature == "" {
+		ah.Nonce = strconv.FormatInt(time.Now().UnixNano(), 10)
+	} else if ah.Nonce, ok = normalizeNonce(ah.Nonce); !ok {
+		fmt.Println("invalid order nonce")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid order nonce")
+	}
+	order := eths.Order{
+		Buyer:   address,
+		Seller:  ah.Address,
 		TokenID: ah.TokenID,
-		Address: ah.Address,
-		Weight:  -ah.Weight,
+		Weight:  ah.Weight,
+		Price:   listing.Price,
+		Nonce:   ah.Nonce,
+	}
+	if ah.Signature == "" {
+		ah.Signature, err = eths.SignTypedData(address, pass, order.TypedData(ch))
+		if err != nil {
+			resp.Errno = utils.RECODE_ETHERR
+			return err
+		}
+	} else if err = eths.VerifyTypedData(order.TypedData(ch), ah.Signature, address); err != nil {
+		fmt.Println("invalid order signature", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
 	}
-	err = equityRegistration.AddEquityRegistration()
+	// 同一买家的订单nonce只能使用一次，防止签名被重放
+	consumed, err := dbs.ConsumeNonce(ch.ChainID(), address, dbs.NONCE_ORDER, ah.Nonce)
 	if err != nil {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
-	// 买家增加库存，没有则新增一条数据
-	equityRegistration.Weight = ah.Weight
-	equityRegistration.Address = address
-	err = equityRegistration.AddEquityRegistration()
+	if !consumed {
+		fmt.Println("order nonce already used", address, ah.Nonce)
+		resp.Errno = utils.RECODE_REPEATERR
+		return errors.New("order nonce already used")
+	}
+
+	//4. 数据库操作-记录成交，扣减卖家挂牌份额并预先转移股权登记，交割失败时撤销
+	err = ah.Add()
+	if err == dbs.ErrListingInsufficient {
+		fmt.Println("listing has insufficient weight", ah.TokenID, ah.Address)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
 	if err != nil {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
 
-	//5. eth 交割
-	err = eths.TransferPXC(address, pass, ah.Address, big.NewInt(ah.Weight*ah.Price))
-	if err != nil {
-		resp.Errno = utils.RECODE_ETHERR
+	//5. eth 交割，按签名订单中的单价（PXC最小单位）通过结算合约原子结算
+	// 二次销售（卖家不是创作者）时按版税比例将部分货款付给创作者
+	content := dbs.Content{}
+	if err = content.QueryByTokenID(ah.TokenID); err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		failTrade(ah)
 		return err
 	}
-	//func PartTransferPXA(from, to string, tokenid, weight, price *big.Int)
+	royalty := big.NewInt(0)
+	if content.Creator != "" && common.HexToAddress(content.Creator) != common.HexToAddress(ah.Address) {
+		ah.Creator, royalty = content.RoyaltyInfo(total)
+	}
+	ah.Royalty = royalty.String()
+	// 付款、版税分账和份额转移在结算合约中一笔交易完成，任一步失败整体回滚
 	value := big.NewInt(0)
 	value, _ = value.SetString(ah.TokenID, 10)
-	err = eths.PartTransferPXA(ah.Address, address, value, big.NewInt(ah.Weight), big.NewInt(ah.Price))
+	tx, err := ch.SettleTrade(address, ah.Address, ah.Creator, value, big.NewInt(ah.Weight), price, royalty)
 	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		if errors.Is(err, eths.ErrAllowanceInsufficient) {
+			resp.Errno = utils.RECODE_PARAMERR
+		}
+		failTrade(ah)
+		return err
+	}
+	//6. 广播前先记录交割交易哈希，广播后由确认任务跟踪，达到确认深度前状态为pending
+	ah.TxHash = tx.Hash().Hex()
+	if err = ah.UpdateTx(); err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		failTrade(ah)
+		return err
+	}
+	resp.Data = map[string]interface{}{
+		"id":      ah.ID,
+		"tx_hash": ah.TxHash,
+		"creator": ah.Creator,
+		"royalty": pxc.Format(royalty),
+		"status":  dbs.STATUS_PENDING,
+	}
+	markIrreversible(c)
+	if err = ch.SendTransaction(tx); err != nil {
+		// 节点可能已收到交易，保留pending状态，由确认任务按交易哈希判定成交或超时撤销
+		fmt.Println("settlement transaction of trade", ah.ID, "may not be broadcast", ah.TxHash)
 		resp.Errno = utils.RECODE_ETHERR
 		return err
 	}
 	return nil
 }
 
+// 撤销尚未广播交割交易的成交记录，撤销失败时记录日志，挂牌份额和股权登记需通过对账接口修复
+func failTrade(ah *dbs.AuctionHis) {
+	if err := ah.Fail(); err != nil {
+		fmt.Println("failed to rollback reservation of trade", ah.ID, ah.TokenID, ah.Address, err)
+	}
+}
+
 // 查询当前用户的拍卖列表 GET /myauctions
 func GetMyAuctions(c echo.Context) error {
 	//1. 响应数据结构初始化
@@ -792,6 +1410,10 @@ func GetMyAuctions(c echo.Context) error {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
+	if err = formatListingPrices(c, auctions); err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
 
 	resp.Data = auctions
 
@@ -833,13 +1455,26 @@ func DeleteAuction(c echo.Context) error {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
-	// 取消授权
+	ch, err := tokenChain(c, tokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	// 取消授权，批量授权给平台时挂牌未单独授权
 	tokenIDBig, ok := new(big.Int).SetString(tokenID, 10)
 	if !ok {
 		resp.Errno = utils.RECODE_PARAMERR
 		return errors.New("invalid tokenID format")
 	}
-	err = eths.CancelApprove(address, pass, tokenIDBig)
+	approved, err := ch.IsApprovedForAll(address, ch.MarketAddress())
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	if approved {
+		return nil
+	}
+	err = ch.CancelApprove(address, pass, tokenIDBig)
 	if err != nil {
 		resp.Errno = utils.RECODE_ETHERR
 		return err
@@ -898,6 +1533,10 @@ func GetAuctionHistory(c echo.Context) error {
 		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
+	if err = formatTradeAmounts(c, pageResult.Rows); err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
 
 	//5. 组织响应数据
 	resp.Data = pageResult
@@ -905,6 +1544,90 @@ func GetAuctionHistory(c echo.Context) error {
 	return nil
 }
 
+// 按token所在链的代币精度将链上最小单位的金额换算为PXC金额，units缓存已读取的代币单位
+func formatPXCUnits(c echo.Context, units map[string]*eths.Money, tokenID, value string) (string, error) {
+	pxc, ok := units[tokenID]
+	if !ok {
+		ch, err := tokenChain(c, tokenID)
+		if err != nil {
+			return value, err
+		}
+		if pxc, err = ch.PXC(); err != nil {
+			return value, err
+		}
+		units[tokenID] = pxc
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return value, nil
+	}
+	return pxc.Format(v), nil
+}
+
+// 将成交记录的单价和版税金额换算为PXC金额
+func formatTradeAmounts(c echo.Context, rows []dbs.AuctionHis) error {
+	units := map[string]*eths.Money{}
+	for i := range rows {
+		var err error
+		if rows[i].Price, err = formatPXCUnits(c, units, rows[i].TokenID, rows[i].Price); err != nil {
+			return err
+		}
+		if rows[i].Royalty, err = formatPXCUnits(c, units, rows[i].TokenID, rows[i].Royalty); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 将挂牌单价换算为PXC金额
+func formatListingPrices(c echo.Context, auctions []dbs.Auction) error {
+	units := map[string]*eths.Money{}
+	for i := range auctions {
+		var err error
+		if auctions[i].Price, err = formatPXCUnits(c, units, auctions[i].TokenID, auctions[i].Price); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 查询当前用户作为创作者收到的版税记录 GET /royalty/history
+func GetRoyaltyHistory(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.处理session
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("failed to get session")
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	address, ok := sess.Values["address"].(string)
+	if address == "" || !ok {
+		fmt.Println("failed to get session,address is nil")
+		resp.Errno = utils.RECODE_LOGINERR
+		return errors.New("please login first")
+	}
+	//3.获取分页参数
+	pageNum, _ := strconv.Atoi(c.QueryParam("pageNum"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	//4.调用数据库分页查询方法
+	pageResult, err := dbs.QueryRoyaltyHis(address, pageNum, pageSize)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if err = formatTradeAmounts(c, pageResult.Rows); err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	//5. 组织响应数据
+	resp.Data = pageResult
+	return nil
+}
+
 // 查询Token所有者 GET /token/owner
 func GetTokenOwner(c echo.Context) error {
 	//1. 响应数据结构初始化
@@ -925,8 +1648,13 @@ func GetTokenOwner(c echo.Context) error {
 		resp.Errno = utils.RECODE_PARAMERR
 		return errors.New("invalid tokenID format")
 	}
+	ch, err := tokenChain(c, tokenIDStr)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
 	//4.调用以太坊合约查询所有者
-	owner, err := eths.GetTokenOwner(tokenID)
+	owner, err := ch.GetTokenOwner(tokenID)
 	if err != nil {
 		fmt.Println("failed to get token owner:", err)
 		resp.Errno = utils.RECODE_ETHERR
@@ -940,61 +1668,705 @@ func GetTokenOwner(c echo.Context) error {
 	return nil
 }
 
-// 代币发放 POST /token/mint
-func MintToken(c echo.Context) error {
+// 整体转让版权NFT POST /pxa721/transfer，将完整持有的原始token赠送或转移给其他平台用户
+func TransferPXA(c echo.Context) error {
 	//1. 响应数据结构初始化
 	var resp utils.Resp
 	resp.Errno = utils.RECODE_OK
 	defer ResponseData(c, &resp)
-	//2.处理session，确保是管理员操作
+	//2.处理session
 	sess, err := session.Get(c.Request(), "session")
 	if err != nil {
 		fmt.Println("failed to get session")
 		resp.Errno = utils.RECODE_LOGINERR
 		return err
 	}
-	address, ok := sess.Values["address"].(string)
-	if address == "" || !ok {
+	address, _ := sess.Values["address"].(string)
+	pass, ok := sess.Values["password"].(string)
+	if address == "" || !ok || pass == "" {
 		fmt.Println("failed to get session,address is nil")
 		resp.Errno = utils.RECODE_LOGINERR
+		return errors.New("please login first")
+	}
+	//3.获取请求参数
+	req := struct {
+		To      string `json:"to"`
+		TokenID string `json:"token_id"`
+	}{}
+	if err = c.Bind(&req); err != nil {
+		fmt.Println("failed to bind request body", err)
+		resp.Errno = utils.RECODE_PARAMERR
 		return err
 	}
-	//3.获取请求体参数
-	type MintRequest struct {
-		To    string `json:"to"`
-		Value string `json:"value"`
+	tokenID, ok := new(big.Int).SetString(req.TokenID, 10)
+	if !ok || !common.IsHexAddress(req.To) || common.HexToAddress(req.To) == common.HexToAddress(address) {
+		fmt.Println("invalid transfer parameters")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid transfer parameters")
 	}
-	var req MintRequest
-	err = c.Bind(&req)
+	// 接收方必须是平台用户
+	receiver := dbs.User{}
+	found, err := receiver.QueryByAddress(req.To)
 	if err != nil {
-		fmt.Println("failed to bind request body", err)
-		resp.Errno = utils.RECODE_PARAMERR
+		resp.Errno = utils.RECODE_DBERR
 		return err
 	}
-	if req.To == "" || req.Value == "" {
-		fmt.Println("to address and value are required")
+	if !found {
+		fmt.Println("receiver is not a platform user", req.To)
 		resp.Errno = utils.RECODE_PARAMERR
-		return errors.New("to address and value are required")
+		return errors.New("receiver is not a platform user")
 	}
-	//4.转换value为big.Int
-	value, ok := new(big.Int).SetString(req.Value, 10)
-	if !ok {
-		fmt.Println("invalid value format")
+	ch, err := tokenChain(c, req.TokenID)
+	if err != nil {
 		resp.Errno = utils.RECODE_PARAMERR
-		return errors.New("invalid value format")
+		return err
 	}
-	to := req.To
-	//5.调用代币发放函数
-	err = eths.MintToken(to, value)
+	//4.校验当前用户持有原始token及全部100份额
+	owner, err := ch.GetTokenOwner(tokenID)
 	if err != nil {
-		fmt.Println("failed to mint token:", err)
 		resp.Errno = utils.RECODE_ETHERR
 		return err
 	}
-	//6.组织响应数据
-	resp.Data = map[string]interface{}{
-		"to":    to,
-		"value": value.String(),
+	_, weight, err := ch.GetShares(tokenID, address)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	if owner != common.HexToAddress(address) || weight.Cmp(big.NewInt(100)) != 0 {
+		fmt.Println("only the owner of all shares can transfer the whole token")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("only the owner of all shares can transfer the whole token")
+	}
+	// 挂牌中或交割未确认的资产不允许转让
+	auction := dbs.Auction{TokenID: req.TokenID, Address: address}
+	listed, err := auction.CountByAddressAndTokenID()
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	pending, err := dbs.CountPendingTrades(req.TokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if listed > 0 || pending > 0 {
+		fmt.Println("token is on sale or has pending trades", req.TokenID)
+		resp.Errno = utils.RECODE_REPEATERR
+		return errors.New("token is on sale or has pending trades")
+	}
+	//5. eth 由owner签名，一笔交易转移全部拆分份额及原始token所有权，成交单价保持不变
+	price, err := ch.GetTokenPrice(tokenID)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	markIrreversible(c)
+	tx, err := ch.TransferPXA(address, pass, req.To, tokenID, price)
+	if err != nil {
+		// 等待超时的交易仍可能上链，返回交易哈希便于核对，份额差异可通过对账接口修复
+		if tx != nil {
+			resp.Data = map[string]string{"tx_hash": tx.Hash().Hex()}
+		}
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	//6.数据库操作-更新内容归属和股权登记
+	content := dbs.Content{TokenID: req.TokenID, Address: address}
+	if err = content.TransferTo(req.To, weight.Int64()); err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	resp.Data = map[string]interface{}{
+		"token_id": req.TokenID,
+		"from":     address,
+		"to":       req.To,
+		"tx_hash":  tx.Hash().Hex(),
+	}
+	return nil
+}
+
+// 批量授权operator管理当前用户的全部版权NFT POST /pxa721/operators，operator为空时授权给平台地址
+func AddOperator(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.处理session
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("failed to get session")
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	address, _ := sess.Values["address"].(string)
+	pass, ok := sess.Values["password"].(string)
+	if address == "" || !ok {
+		fmt.Println("failed to get session,address is nil")
+		resp.Errno = utils.RECODE_LOGINERR
+		return errors.New("please login first")
+	}
+	//3.获取请求参数
+	req := struct {
+		Operator string `json:"operator"`
+	}{}
+	if err = c.Bind(&req); err != nil {
+		fmt.Println("failed to bind request body", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	if req.Operator == "" {
+		req.Operator = ch.MarketAddress()
+	}
+	if !common.IsHexAddress(req.Operator) || common.HexToAddress(req.Operator) == common.HexToAddress(address) {
+		fmt.Println("invalid operator", req.Operator)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid operator")
+	}
+	//4.调用合约授权
+	tx, err := ch.SetApprovalForAll(address, pass, req.Operator)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	resp.Data = map[string]interface{}{
+		"owner":    address,
+		"operator": req.Operator,
+		"approved": true,
+		"tx_hash":  tx.Hash().Hex(),
+	}
+	return nil
+}
+
+// 取消operator批量授权 DELETE /pxa721/operators?operator=0x...，operator为空时取消平台地址的授权
+func RemoveOperator(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.处理session
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("failed to get session")
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	address, _ := sess.Values["address"].(string)
+	pass, ok := sess.Values["password"].(string)
+	if address == "" || !ok {
+		fmt.Println("failed to get session,address is nil")
+		resp.Errno = utils.RECODE_LOGINERR
+		return errors.New("please login first")
+	}
+	//3.获取请求参数
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	operator := c.QueryParam("operator")
+	if operator == "" {
+		operator = ch.MarketAddress()
+	}
+	if !common.IsHexAddress(operator) {
+		fmt.Println("invalid operator", operator)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid operator")
+	}
+	//4.调用合约取消授权
+	err = ch.CancelApprovalForAll(address, pass, operator)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	resp.Data = map[string]interface{}{
+		"owner":    address,
+		"operator": operator,
+		"approved": false,
+	}
+	return nil
+}
+
+// 查询operator批量授权状态 GET /pxa721/operators?owner=0x...&operator=0x...
+func GetOperators(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.获取请求参数，owner缺省为当前登录用户
+	owner := c.QueryParam("owner")
+	if owner == "" {
+		sess, err := session.Get(c.Request(), "session")
+		if err != nil {
+			fmt.Println("Failed to get session", err)
+			resp.Errno = utils.RECODE_LOGINERR
+			return err
+		}
+		userAddress, ok := sess.Values["address"].(string)
+		if !ok || userAddress == "" {
+			fmt.Println("Failed to get address from session")
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("owner parameter is required")
+		}
+		owner = userAddress
+	}
+	if !common.IsHexAddress(owner) {
+		fmt.Println("invalid owner", owner)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid owner")
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//3.指定operator时只查询该地址
+	if operator := c.QueryParam("operator"); operator != "" {
+		if !common.IsHexAddress(operator) {
+			fmt.Println("invalid operator", operator)
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("invalid operator")
+		}
+		approved, err := ch.IsApprovedForAll(owner, operator)
+		if err != nil {
+			resp.Errno = utils.RECODE_ETHERR
+			return err
+		}
+		resp.Data = map[string]interface{}{
+			"owner":    owner,
+			"operator": operator,
+			"approved": approved,
+		}
+		return nil
+	}
+	//4.查询全部operator
+	operators, err := ch.QueryOperators(owner)
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	resp.Data = map[string]interface{}{
+		"owner":     owner,
+		"operators": operators,
+	}
+	return nil
+}
+
+// ERC-721元数据中的份额结构
+type MetadataShare struct {
+	Address string `json:"address"` //持有人地址
+	Weight  int64  `json:"weight"`  //持有份额（百分比）
+}
+
+// ERC-721元数据中的属性
+type MetadataAttribute struct {
+	DisplayType string      `json:"display_type,omitempty"`
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+}
+
+// ERC-721元数据（tokenURI指向的JSON）
+type Metadata struct {
+	Name         string              `json:"name"`                    //图片名称
+	Description  string              `json:"description"`             //描述
+	Image        string              `json:"image,omitempty"`         //图片预览图链接
+	ExternalURL  string              `json:"external_url,omitempty"`  //登记核验页链接
+	MimeType     string              `json:"mime_type"`               //内容MIME类型
+	TokenID      string              `json:"token_id"`                //原始tokenid
+	ChainID      string              `json:"chain_id"`                //铸造所在链ID
+	ContentHash  string              `json:"content_hash"`            //图片内容哈希
+	CID          string              `json:"cid,omitempty"`           //IPFS CIDv1
+	Creator      string              `json:"creator"`                 //创作者地址
+	Owner        string              `json:"owner"`                   //当前归属地址
+	RoyaltyBps   int64               `json:"seller_fee_basis_points"` //二次销售版税（万分比）
+	FeeRecipient string              `json:"fee_recipient"`           //版税收款地址
+	CreatedAt    string              `json:"created_at"`              //上传时间
+	Shares       []MetadataShare     `json:"shares"`                  //份额结构
+	Attributes   []MetadataAttribute `json:"attributes"`              //钱包和浏览器展示用的属性
+}
+
+// 版权NFT元数据 GET /metadata/:tokenId
+// 按ERC-721元数据规范直接返回JSON，不使用统一的响应结构，以便钱包和区块浏览器解析
+func GetMetadata(c echo.Context) error {
+	//1.获取请求参数
+	tokenID := c.Param("tokenId")
+	if _, ok := new(big.Int).SetString(tokenID, 10); !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid token id"})
+	}
+	//2.查询图片信息
+	content := dbs.Content{}
+	if err := content.QueryByTokenID(tokenID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": utils.RecodeText(utils.RECODE_DBERR)})
+	}
+	if content.TokenID == "" {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "token not found"})
+	}
+	//3.查询份额结构
+	holders, err := dbs.QueryEquityHolders(content.ChainID, tokenID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": utils.RecodeText(utils.RECODE_DBERR)})
+	}
+	shares := []MetadataShare{}
+	for _, h := range holders {
+		if h.Weight > 0 {
+			shares = append(shares, MetadataShare{Address: h.Address, Weight: h.Weight})
+		}
+	}
+	//4.组织元数据，图片使用绝对地址
+	baseURL := publicURL(c)
+	meta := Metadata{
+		Name:         content.Title,
+		Description:  fmt.Sprintf("数字版权 #%s，内容哈希 %s", tokenID, content.ContentHash),
+		TokenID:      tokenID,
+		ChainID:      content.ChainID,
+		ContentHash:  content.ContentHash,
+		CID:          content.CID,
+		MimeType:     content.MimeType,
+		Creator:      content.Creator,
+		Owner:        content.Address,
+		RoyaltyBps:   content.RoyaltyBps,
+		FeeRecipient: content.Creator,
+		CreatedAt:    content.CreatedAt,
+		Shares:       shares,
+		Attributes: []MetadataAttribute{
+			{TraitType: "Content Hash", Value: content.ContentHash},
+			{TraitType: "Creator", Value: content.Creator},
+			{TraitType: "Holders", Value: len(shares)},
+		},
+	}
+	//5.原件仅限份额持有人访问，公开元数据只链接公开的派生内容：图片使用带水印的预览图（或缩略图），
+	// 尚未生成预览图的图片和其他类别不提供内容链接；external_url指向公开的登记核验页
+	kind, _, _ := configs.UploadTypeOf(content.MimeType)
+	if content.MimeType == "" {
+		kind = configs.UPLOAD_IMAGE
+	}
+	if kind == configs.UPLOAD_IMAGE {
+		if content.Preview != "" {
+			meta.Image = baseURL + content.Preview
+		} else if content.Thumbnail != "" {
+			meta.Image = baseURL + content.Thumbnail
+		}
+	}
+	meta.ExternalURL = baseURL + "/content/" + tokenID + "/verify"
+	if kind != "" {
+		meta.Attributes = append(meta.Attributes, MetadataAttribute{TraitType: "Media Type", Value: kind})
+	}
+	if content.CID != "" {
+		meta.Attributes = append(meta.Attributes, MetadataAttribute{TraitType: "IPFS CID", Value: content.CID})
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", content.CreatedAt, time.Local); err == nil {
+		meta.Attributes = append(meta.Attributes, MetadataAttribute{DisplayType: "date", TraitType: "Created", Value: t.Unix()})
+	}
+	return c.JSON(http.StatusOK, meta)
+}
+
+// 查询持有人链上份额 GET /token/shares?token_id=...&address=0x...
+func GetTokenShares(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.获取请求参数
+	tokenIDStr := c.QueryParam("token_id")
+	tokenID, ok := new(big.Int).SetString(tokenIDStr, 10)
+	if !ok {
+		fmt.Println("invalid tokenID format")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid tokenID format")
+	}
+	address := c.QueryParam("address")
+	if address == "" {
+		// 如果没有提供地址参数，尝试从session中获取当前用户的地址
+		sess, err := session.Get(c.Request(), "session")
+		if err != nil {
+			fmt.Println("Failed to get session", err)
+			resp.Errno = utils.RECODE_LOGINERR
+			return err
+		}
+		userAddress, ok := sess.Values["address"].(string)
+		if !ok || userAddress == "" {
+			fmt.Println("Failed to get address from session")
+			resp.Errno = utils.RECODE_PARAMERR
+			return errors.New("address parameter is required")
+		}
+		address = userAddress
+	}
+	ch, err := tokenChain(c, tokenIDStr)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//3.查询链上份额
+	splitID, weight, err := ch.GetShares(tokenID, address)
+	if err != nil {
+		fmt.Println("failed to get token shares:", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	//4.组织响应数据
+	resp.Data = map[string]string{
+		"token_id":       tokenIDStr,
+		"address":        address,
+		"split_token_id": splitID.String(),
+		"weight":         weight.String(),
+	}
+	return nil
+}
+
+// 查询原始token最近成交单价 GET /token/price?token_id=...
+func GetTokenPrice(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.获取请求参数
+	tokenIDStr := c.QueryParam("token_id")
+	tokenID, ok := new(big.Int).SetString(tokenIDStr, 10)
+	if !ok {
+		fmt.Println("invalid tokenID format")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid tokenID format")
+	}
+	ch, err := tokenChain(c, tokenIDStr)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//3.查询链上成交单价
+	price, err := ch.GetTokenPrice(tokenID)
+	if err != nil {
+		fmt.Println("failed to get token price:", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	//4.组织响应数据
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	resp.Data = map[string]string{
+		"token_id": tokenIDStr,
+		"price":    pxc.Format(price),
+		"symbol":   pxc.Symbol,
+	}
+	return nil
+}
+
+// 代币发放 POST /token/mint
+func MintToken(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2.处理session，确保是管理员操作
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("failed to get session")
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	address, ok := sess.Values["address"].(string)
+	if address == "" || !ok {
+		fmt.Println("failed to get session,address is nil")
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	//3.获取请求体参数
+	type MintRequest struct {
+		To    string `json:"to"`
+		Value string `json:"value"`
+	}
+	var req MintRequest
+	err = c.Bind(&req)
+	if err != nil {
+		fmt.Println("failed to bind request body", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	if req.To == "" || req.Value == "" {
+		fmt.Println("to address and value are required")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("to address and value are required")
+	}
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//4.按代币精度将十进制金额转换为链上最小单位
+	pxc, err := ch.PXC()
+	if err != nil {
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	value, err := pxc.Parse(req.Value)
+	if err != nil || value.Sign() <= 0 {
+		fmt.Println("invalid value format")
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid value format")
+	}
+	to := req.To
+	//5.调用代币发放函数
+	markIrreversible(c)
+	err = ch.MintToken(to, value)
+	if err != nil {
+		fmt.Println("failed to mint token:", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	//6.组织响应数据
+	resp.Data = map[string]interface{}{
+		"to":     to,
+		"value":  pxc.Format(value),
+		"symbol": pxc.Symbol,
+	}
+	return nil
+}
+
+// SSE心跳间隔，防止代理关闭空闲连接
+const STREAM_HEARTBEAT = 15 * time.Second
+
+// 实时推送登录用户相关的链上事件 GET /events/stream (Server-Sent Events)
+func StreamEvents(c echo.Context) error {
+	//1. 处理session
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		fmt.Println("failed to get session")
+		ResponseData(c, &utils.Resp{Errno: utils.RECODE_LOGINERR})
+		return err
+	}
+	address, ok := sess.Values["address"].(string)
+	if address == "" || !ok {
+		fmt.Println("failed to get session,address is nil")
+		ResponseData(c, &utils.Resp{Errno: utils.RECODE_LOGINERR})
+		return errors.New("please login first")
+	}
+	//2. 订阅所选链上的事件
+	ch, err := requestChain(c)
+	if err != nil {
+		ResponseData(c, &utils.Resp{Errno: utils.RECODE_PARAMERR})
+		return err
+	}
+	sub, err := ch.SubscribeEvents(address)
+	if err != nil {
+		fmt.Println("failed to subscribe events", err)
+		ResponseData(c, &utils.Resp{Errno: utils.RECODE_ETHERR})
+		return err
+	}
+	defer sub.Unsubscribe()
+	//3. 建立事件流
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(STREAM_HEARTBEAT)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			fmt.Fprint(res, ": ping\n\n")
+			res.Flush()
+		case ev, ok := <-sub.C:
+			// 链上订阅中断，浏览器EventSource会自动重连
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				fmt.Println("failed to marshal event", err)
+				continue
+			}
+			fmt.Fprintf(res, "event: %s\ndata: %s\n\n", ev.Event, data)
+			res.Flush()
+		}
+	}
+}
+
+// 获取登录的ch链管理员地址，非管理员返回错误
+func adminAddress(c echo.Context, ch *eths.Chain) (string, error) {
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		return "", err
+	}
+	address, ok := sess.Values["address"].(string)
+	if address == "" || !ok {
+		return "", errors.New("please login first")
+	}
+	if !ch.IsAdmin(address) {
+		return "", errors.New("admin only")
+	}
+	return address, nil
+}
+
+// 股权对账报告 GET /admin/reconcile?token_id=
+func GetReconcile(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//2. 管理员校验
+	if _, err := adminAddress(c, ch); err != nil {
+		fmt.Println("failed to check admin", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	//3. 核对数据库与链上份额
+	discrepancies, err := ch.Reconcile(c.QueryParam("token_id"))
+	if err != nil {
+		fmt.Println("failed to reconcile equity", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	resp.Data = discrepancies
+	return nil
+}
+
+// 按链上份额修复股权登记 POST /admin/reconcile?token_id=
+func RepairReconcile(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	ch, err := requestChain(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_PARAMERR
+		return err
+	}
+	//2. 管理员校验
+	if _, err := adminAddress(c, ch); err != nil {
+		fmt.Println("failed to check admin", err)
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	//3. 核对数据库与链上份额
+	discrepancies, err := ch.Reconcile(c.QueryParam("token_id"))
+	if err != nil {
+		fmt.Println("failed to reconcile equity", err)
+		resp.Errno = utils.RECODE_ETHERR
+		return err
+	}
+	//4. 以链上数据为准修复数据库
+	err = ch.RepairEquity(discrepancies)
+	if err != nil {
+		fmt.Println("failed to repair equity", err)
+		resp.Errno = utils.RECODE_DBERR
+		return err
 	}
+	resp.Data = discrepancies
 	return nil
 }
@@ -0,0 +1,501 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"copyright/configs"
+	"copyright/dbs"
+	"copyright/eths"
+	"copyright/storage"
+	"copyright/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 上传临时目录，与本地存储目录位于同一文件系统以便原子重命名，不对外提供静态访问
+const UPLOAD_TMP_DIR = "static/uploads"
+
+// PDF文件尾标记，缺失说明文件被截断
+var pdfEOF = []byte("%%EOF")
+
+// 同一分片上传的分片串行写入
+var uploadLocks sync.Map
+
+// 暂存的上传文件：已写入临时目录并计算哈希，校验通过后再保存到存储后端
+type stagedFile struct {
+	path     string //临时文件路径
+	title    string //原始文件名
+	size     int64  //文件大小
+	mimeType string //按文件内容识别的MIME类型
+	kind     string //上传类别
+	hash     string //Keccak256内容哈希
+	cid      string //IPFS CIDv1
+	phash    string //感知哈希，仅图片
+}
+
+// 按文件头识别MIME类型，校验类型白名单和该类别的大小上限
+func sniffType(head []byte, size int64, resp *utils.Resp) (string, configs.UploadType, string, error) {
+	mimeType := utils.SniffMIME(head)
+	kind, limit, ok := configs.UploadTypeOf(mimeType)
+	if !ok {
+		fmt.Println("unsupported file type", mimeType)
+		resp.Errno = utils.RECODE_FILETYPEERR
+		return "", limit, "", errors.New("unsupported file type " + mimeType)
+	}
+	if size > limit.MaxSize {
+		fmt.Println("file too large", size, kind, limit.MaxSize)
+		resp.Errno = utils.RECODE_FILESIZEERR
+		return "", limit, "", errors.New("file too large")
+	}
+	return kind, limit, mimeType, nil
+}
+
+// 将表单文件流式写入临时文件，写入的同时通过TeeReader计算哈希，不在内存中保留整个文件
+func stageFormFile(h *multipart.FileHeader, resp *utils.Resp) (*stagedFile, error) {
+	src, err := h.Open()
+	if err != nil {
+		fmt.Println("failed to open file ", err)
+		resp.Errno = utils.RECODE_SYSERR
+		return nil, err
+	}
+	defer src.Close()
+	//1. 读取文件头识别类型
+	head := make([]byte, utils.SNIFF_LEN)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		fmt.Println("failed to read file head ", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return nil, err
+	}
+	kind, limit, mimeType, err := sniffType(head[:n], h.Size, resp)
+	if err != nil {
+		return nil, err
+	}
+	//2. 写入临时文件，超过大小上限时中止
+	tmp, err := os.CreateTemp(UPLOAD_TMP_DIR, "upload-*")
+	if err != nil {
+		fmt.Println("failed to create temp file ", err)
+		resp.Errno = utils.RECODE_SYSERR
+		return nil, err
+	}
+	defer tmp.Close()
+	f := &stagedFile{path: tmp.Name(), title: h.Filename, mimeType: mimeType, kind: kind}
+	hasher := eths.NewKeccak()
+	cid := utils.NewCIDBuilder()
+	r := io.TeeReader(io.MultiReader(bytes.NewReader(head[:n]), src), io.MultiWriter(hasher, cid))
+	f.size, err = io.Copy(tmp, io.LimitReader(r, limit.MaxSize+1))
+	if err != nil {
+		fmt.Println("failed to write temp file ", err)
+		f.discard()
+		resp.Errno = utils.RECODE_SYSERR
+		return nil, err
+	}
+	if f.size > limit.MaxSize {
+		fmt.Println("file too large", f.size, kind, limit.MaxSize)
+		f.discard()
+		resp.Errno = utils.RECODE_FILESIZEERR
+		return nil, errors.New("file too large")
+	}
+	f.hash = fmt.Sprintf("%x", hasher.Sum(nil))
+	f.cid = cid.Sum()
+	//3. 按类别校验内容
+	if err = f.check(); err != nil {
+		f.discard()
+		resp.Errno = utils.RECODE_FILETYPEERR
+		return nil, err
+	}
+	return f, nil
+}
+
+// 取出已接收完整的分片上传文件，识别类型并计算哈希
+func stageChunkedUpload(upload *dbs.Upload, resp *utils.Resp) (*stagedFile, error) {
+	if upload.Received != upload.Size {
+		fmt.Println("upload is incomplete", upload.UploadID, upload.Received, upload.Size)
+		resp.Errno = utils.RECODE_PARAMERR
+		return nil, fmt.Errorf("upload is incomplete: %d/%d", upload.Received, upload.Size)
+	}
+	src, err := os.Open(chunkPath(upload.UploadID))
+	if err != nil {
+		fmt.Println("failed to open upload file ", err)
+		resp.Errno = utils.RECODE_SYSERR
+		return nil, err
+	}
+	defer src.Close()
+	//1. 读取文件头识别类型
+	head := make([]byte, utils.SNIFF_LEN)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		fmt.Println("failed to read file head ", err)
+		resp.Errno = utils.RECODE_PARAMERR
+		return nil, err
+	}
+	kind, _, mimeType, err := sniffType(head[:n], upload.Size, resp)
+	if err != nil {
+		return nil, err
+	}
+	//2. 计算哈希和CID
+	hasher := eths.NewKeccak()
+	cid := utils.NewCIDBuilder()
+	w := io.MultiWriter(hasher, cid)
+	w.Write(head[:n])
+	if _, err = io.Copy(w, src); err != nil {
+		fmt.Println("failed to hash upload file ", err)
+		resp.Errno = utils.RECODE_SYSERR
+		return nil, err
+	}
+	f := &stagedFile{
+		path:     chunkPath(upload.UploadID),
+		title:    upload.FileName,
+		size:     upload.Size,
+		mimeType: mimeType,
+		kind:     kind,
+		hash:     fmt.Sprintf("%x", hasher.Sum(nil)),
+		cid:      cid.Sum(),
+	}
+	//3. 按类别校验内容
+	if err = f.check(); err != nil {
+		resp.Errno = utils.RECODE_FILETYPEERR
+		return nil, err
+	}
+	return f, nil
+}
+
+// 按文件类别校验上传内容，图片同时计算感知哈希
+func (f *stagedFile) check() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		fmt.Println("failed to open staged file", err)
+		return err
+	}
+	defer file.Close()
+	switch f.kind {
+	case configs.UPLOAD_IMAGE:
+		//1. 图片必须能够解码且像素数不超过上限，并计算感知哈希用于相似度检测
+		cfg, _, err := image.DecodeConfig(bufio.NewReader(file))
+		if err != nil {
+			fmt.Println("failed to decode image", err)
+			return err
+		}
+		if int64(cfg.Width)*int64(cfg.Height) > configs.ImageMaxPixels() {
+			fmt.Println("image is too large", cfg.Width, cfg.Height)
+			return utils.ErrImageTooLarge
+		}
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if f.phash, err = utils.DHash(bufio.NewReader(file), configs.ImageMaxPixels()); err != nil {
+			fmt.Println("failed to compute perceptual hash", err)
+			return err
+		}
+	case configs.UPLOAD_PDF:
+		//2. PDF文件尾部需有%%EOF标记
+		tail := make([]byte, 1024)
+		offset := f.size - int64(len(tail))
+		if offset < 0 {
+			offset = 0
+		}
+		n, err := file.ReadAt(tail, offset)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if !bytes.Contains(tail[:n], pdfEOF) {
+			return errors.New("truncated pdf")
+		}
+	case configs.UPLOAD_TEXT:
+		//3. 文本作品统一按UTF-8保存，逐字符校验
+		r := bufio.NewReader(file)
+		for {
+			ch, size, err := r.ReadRune()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if ch == utf8.RuneError && size == 1 {
+				return errors.New("text is not valid utf-8")
+			}
+		}
+	}
+	// 音频和视频按文件头识别类型即可，元数据中以animation_url提供
+	return nil
+}
+
+// 由暂存的图片生成缩略图和预览图
+func (f *stagedFile) derive(tokenID string) (thumb, preview string, err error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		fmt.Println("failed to open staged file", err)
+		return "", "", err
+	}
+	defer file.Close()
+	return deriveImages(file, tokenID)
+}
+
+// 哈希确定、校验通过后保存到存储后端（本地存储为原子重命名），返回对外访问路径
+func (f *stagedFile) commit(name string) (string, error) {
+	if err := storage.Default().Save(f.path, name, f.mimeType); err != nil {
+		fmt.Println("failed to save staged file", err)
+		return "", err
+	}
+	f.path = ""
+	return "/contents/" + name, nil
+}
+
+// 丢弃临时文件，已保存到存储后端后不做处理
+func (f *stagedFile) discard() {
+	if f.path != "" {
+		os.Remove(f.path)
+	}
+}
+
+// 分片上传的临时文件路径
+func chunkPath(uploadID string) string {
+	return filepath.Join(UPLOAD_TMP_DIR, uploadID+".part")
+}
+
+// 从session获取登录用户地址
+func sessionAddress(c echo.Context) (string, error) {
+	sess, err := session.Get(c.Request(), "session")
+	if err != nil {
+		return "", err
+	}
+	address, ok := sess.Values["address"].(string)
+	if address == "" || !ok {
+		return "", errors.New("please login first")
+	}
+	return address, nil
+}
+
+// 创建分片上传 POST /content/uploads?file_name=&size=
+func CreateUpload(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	address, err := sessionAddress(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_LOGINERR
+		return err
+	}
+	//2. 文件总大小不能超过任一类别的上限，具体类别在上传完成后识别
+	size, err := strconv.ParseInt(c.FormValue("size"), 10, 64)
+	if err != nil || size <= 0 {
+		fmt.Println("invalid size", c.FormValue("size"))
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("invalid size")
+	}
+	var maxSize int64
+	for _, t := range configs.Conf.Upload {
+		if len(t.MIME) > 0 && t.MaxSize > maxSize {
+			maxSize = t.MaxSize
+		}
+	}
+	if size > maxSize {
+		resp.Errno = utils.RECODE_FILESIZEERR
+		return errors.New("file too large")
+	}
+	//3. 创建会话和空的临时文件
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		resp.Errno = utils.RECODE_SYSERR
+		return err
+	}
+	upload := &dbs.Upload{
+		UploadID: hex.EncodeToString(id),
+		Address:  address,
+		FileName: c.FormValue("file_name"),
+		Size:     size,
+	}
+	tmp, err := os.Create(chunkPath(upload.UploadID))
+	if err != nil {
+		fmt.Println("failed to create upload file", err)
+		resp.Errno = utils.RECODE_SYSERR
+		return err
+	}
+	tmp.Close()
+	if err = upload.AddUpload(); err != nil {
+		os.Remove(chunkPath(upload.UploadID))
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	resp.Data = upload
+	return nil
+}
+
+// 查询分片上传进度，客户端据此从received处续传 GET /content/uploads/:id
+func GetUpload(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	upload, err := loadUpload(c, &resp)
+	if err != nil {
+		return err
+	}
+	resp.Data = upload
+	return nil
+}
+
+// 上传一个分片 POST /content/uploads/:id?offset=，请求体为分片的原始字节
+// offset须等于已接收的字节数，不一致时返回当前进度，客户端从该处续传
+func UploadChunk(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	//2. 会话存在后再加锁，同一上传的分片串行写入
+	upload, err := loadUpload(c, &resp)
+	if err != nil {
+		return err
+	}
+	lock, _ := uploadLocks.LoadOrStore(upload.UploadID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+	// 等待锁期间进度可能已被其他分片更新，会话也可能已被取消或过期清理
+	found, err := upload.QueryUpload(upload.UploadID, upload.Address)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	if !found {
+		uploadLocks.Delete(upload.UploadID)
+		resp.Errno = utils.RECODE_PARAMERR
+		return errors.New("upload not found")
+	}
+	offset, err := strconv.ParseInt(c.QueryParam("offset"), 10, 64)
+	if err != nil || offset != upload.Received {
+		fmt.Println("unexpected chunk offset", c.QueryParam("offset"), upload.Received)
+		resp.Errno = utils.RECODE_PARAMERR
+		resp.Data = upload
+		return errors.New("unexpected chunk offset")
+	}
+	//3. 从偏移处写入，超出声明大小时中止
+	f, err := os.OpenFile(chunkPath(upload.UploadID), os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Println("failed to open upload file", err)
+		resp.Errno = utils.RECODE_SYSERR
+		return err
+	}
+	defer f.Close()
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		resp.Errno = utils.RECODE_SYSERR
+		return err
+	}
+	written, copyErr := io.Copy(f, io.LimitReader(c.Request().Body, upload.Size-offset+1))
+	if offset+written > upload.Size {
+		// 超出部分丢弃，已接收的进度保持不变
+		f.Truncate(offset)
+		resp.Errno = utils.RECODE_FILESIZEERR
+		resp.Data = upload
+		return errors.New("chunk exceeds declared size")
+	}
+	//4. 连接中断时也记录已写入的部分，续传从新的偏移开始
+	if written > 0 {
+		if err = f.Sync(); err != nil {
+			resp.Errno = utils.RECODE_SYSERR
+			return err
+		}
+		if err = upload.UpdateReceived(offset, offset+written); err != nil {
+			resp.Errno = utils.RECODE_DBERR
+			return err
+		}
+	}
+	resp.Data = upload
+	if copyErr != nil {
+		fmt.Println("chunk interrupted", upload.UploadID, copyErr)
+		resp.Errno = utils.RECODE_SYSERR
+		return copyErr
+	}
+	return nil
+}
+
+// 取消分片上传 DELETE /content/uploads/:id
+func DeleteUpload(c echo.Context) error {
+	//1. 响应数据结构初始化
+	var resp utils.Resp
+	resp.Errno = utils.RECODE_OK
+	defer ResponseData(c, &resp)
+	upload, err := loadUpload(c, &resp)
+	if err != nil {
+		return err
+	}
+	if err = upload.DeleteUpload(); err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return err
+	}
+	os.Remove(chunkPath(upload.UploadID))
+	uploadLocks.Delete(upload.UploadID)
+	return nil
+}
+
+// 过期分片上传的清理间隔
+const UPLOAD_CLEAN_INTERVAL = 10 * time.Minute
+
+// 后台定期清理过期的分片上传
+func RunUploadCleaner() {
+	go func() {
+		for {
+			cleanExpiredUploads()
+			time.Sleep(UPLOAD_CLEAN_INTERVAL)
+		}
+	}()
+}
+
+// 删除超过upload_expiry小时没有收到新分片的会话及其临时文件和锁
+func cleanExpiredUploads() {
+	if configs.Conf.UploadExpiry == 0 {
+		return
+	}
+	expiry := time.Duration(configs.Conf.UploadExpiry) * time.Hour
+	uploads, err := dbs.QueryExpiredUploads(expiry)
+	if err != nil {
+		return
+	}
+	for _, upload := range uploads {
+		//1. 与分片写入互斥，加锁期间收到的新分片会刷新更新时间，删除时再次判断是否过期
+		lock, _ := uploadLocks.LoadOrStore(upload.UploadID, &sync.Mutex{})
+		lock.(*sync.Mutex).Lock()
+		deleted, err := upload.DeleteExpired(expiry)
+		//2. 删除临时文件和锁
+		if err == nil && deleted {
+			fmt.Println("remove expired upload", upload.UploadID)
+			os.Remove(chunkPath(upload.UploadID))
+			uploadLocks.Delete(upload.UploadID)
+		}
+		lock.(*sync.Mutex).Unlock()
+	}
+}
+
+// 读取当前用户的分片上传会话
+func loadUpload(c echo.Context, resp *utils.Resp) (*dbs.Upload, error) {
+	address, err := sessionAddress(c)
+	if err != nil {
+		resp.Errno = utils.RECODE_LOGINERR
+		return nil, err
+	}
+	upload := &dbs.Upload{}
+	found, err := upload.QueryUpload(c.Param("id"), address)
+	if err != nil {
+		resp.Errno = utils.RECODE_DBERR
+		return nil, err
+	}
+	if !found {
+		resp.Errno = utils.RECODE_PARAMERR
+		return nil, errors.New("upload not found")
+	}
+	return upload, nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// 本地文件系统存储
+type Local struct {
+	dir string //存储目录
+}
+
+// 创建本地存储，目录不存在时自动创建
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Println("failed to create storage dir", err)
+		return nil, err
+	}
+	return &Local{dir: dir}, nil
+}
+
+// 暂存文件与存储目录位于同一文件系统时为原子重命名
+func (l *Local) Save(path, name, mimeType string) error {
+	if err := os.Rename(path, l.path(name)); err != nil {
+		fmt.Println("failed to move file into storage", err)
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(l.path(name))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return f, err
+}
+
+func (l *Local) Delete(name string) error {
+	err := os.Remove(l.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// 只取文件名部分，防止路径穿越
+func (l *Local) path(name string) string {
+	return filepath.Join(l.dir, filepath.Base(name))
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"copyright/configs"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 请求体不参与签名，S3和MinIO均支持
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3兼容对象存储（AWS S3、MinIO等），使用path-style地址和AWS Signature V4签名
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	cli       *http.Client
+}
+
+// 创建S3存储，存储桶不存在时自动创建
+func NewS3(conf configs.Storage) (*S3, error) {
+	endpoint, err := url.Parse(conf.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", conf.Endpoint)
+	}
+	if conf.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is not configured")
+	}
+	s := &S3{
+		endpoint:  endpoint,
+		region:    conf.Region,
+		bucket:    conf.Bucket,
+		accessKey: conf.AccessKey,
+		secretKey: conf.SecretKey,
+		cli:       &http.Client{},
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	//1. 检查存储桶
+	resp, err := s.do(http.MethodHead, "", nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return s, nil
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		return nil, fmt.Errorf("failed to check bucket %s: %s", s.bucket, resp.Status)
+	}
+	//2. 创建存储桶
+	resp, err = s.do(http.MethodPut, "", nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to create bucket %s: %s", s.bucket, readError(resp))
+	}
+	return s, nil
+}
+
+// 上传本地暂存文件，成功后删除本地文件
+func (s *S3) Save(localPath, name, mimeType string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		fmt.Println("failed to open staged file", err)
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodPut, name, f, info.Size(), mimeType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to put object %s: %s", name, readError(resp))
+	}
+	os.Remove(localPath)
+	return nil
+}
+
+func (s *S3) Open(name string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, name, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotExist
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to get object %s: %s", name, readError(resp))
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(name string) error {
+	resp, err := s.do(http.MethodDelete, name, nil, 0, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to delete object %s: %s", name, readError(resp))
+	}
+	return nil
+}
+
+// 发送签名请求，name为空时操作存储桶本身
+func (s *S3) do(method, name string, body io.Reader, size int64, mimeType string) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = "/" + s.bucket
+	if name != "" {
+		u.Path += "/" + path.Base(name)
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if mimeType != "" {
+		req.Header.Set("Content-Type", mimeType)
+	}
+	s.sign(req, time.Now().UTC())
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		fmt.Println("s3 request failed", method, u.Path, err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AWS Signature Version 4，签名host及请求中已设置的全部请求头；
+// 请求未设置x-amz-content-sha256时请求体不参与签名
+func (s *S3) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	payloadHash := req.Header.Get("x-amz-content-sha256")
+	if payloadHash == "" {
+		payloadHash = unsignedPayload
+		req.Header.Set("x-amz-content-sha256", payloadHash)
+	}
+
+	//1. 规范请求：请求头名称转小写后排序
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if name == "Authorization" {
+			continue
+		}
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	//2. 待签名字符串
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	//3. 派生签名密钥并签名
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// 读取S3错误响应的内容
+func readError(resp *http.Response) string {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.Status + " " + string(data)
+}
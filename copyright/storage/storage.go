@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"copyright/configs"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+)
+
+// 内容对象不存在
+var ErrNotExist = errors.New("object does not exist")
+
+// 内容存储后端
+type Storage interface {
+	// 将暂存在本地的文件保存为name，成功后本地文件不再保留
+	Save(path, name, mimeType string) error
+	// 读取内容，返回的对象实现io.ReadSeeker时支持按范围读取
+	Open(name string) (io.ReadCloser, error)
+	// 删除内容
+	Delete(name string) error
+}
+
+// 当前使用的存储后端
+var store Storage
+
+// init按配置创建存储后端
+func init() {
+	conf := configs.Conf.Storage
+	var err error
+	switch conf.Type {
+	case configs.STORAGE_LOCAL, "":
+		store, err = NewLocal(conf.Dir)
+	case configs.STORAGE_S3:
+		store, err = NewS3(conf)
+	default:
+		err = fmt.Errorf("unknown storage type %s", conf.Type)
+	}
+	if err != nil {
+		log.Panic("Failed to init storage ", err)
+	}
+}
+
+// 当前使用的存储后端
+func Default() Storage {
+	return store
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"strings"
+)
+
+// IPFS默认分块参数：256KiB定长分块，每个中间节点最多174个子节点
+const (
+	CID_CHUNK_SIZE = 256 * 1024
+	CID_MAX_LINKS  = 174
+)
+
+// multicodec编码
+const (
+	codecRaw    = 0x55
+	codecDagPB  = 0x70
+	hashSHA2256 = 0x12
+)
+
+var cidBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// DAG中的一个节点
+type cidNode struct {
+	cid      []byte //CID二进制
+	fileSize uint64 //节点覆盖的文件数据大小
+	treeSize uint64 //节点及全部子节点序列化后的大小
+}
+
+// 流式计算IPFS CIDv1：按256KiB分块，叶子为raw块，按balanced布局组织为UnixFS文件DAG，
+// 与 ipfs add --cid-version=1 的默认参数一致，单个分块的文件即为raw块的CID
+type CIDBuilder struct {
+	buf    []byte
+	leaves []cidNode
+}
+
+func NewCIDBuilder() *CIDBuilder {
+	return &CIDBuilder{buf: make([]byte, 0, CID_CHUNK_SIZE)}
+}
+
+// 写入文件内容
+func (b *CIDBuilder) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		m := copy(b.buf[len(b.buf):CID_CHUNK_SIZE], p)
+		b.buf = b.buf[:len(b.buf)+m]
+		p = p[m:]
+		if len(b.buf) == CID_CHUNK_SIZE {
+			b.flush()
+		}
+	}
+	return n, nil
+}
+
+// 结束写入，返回base32编码的CIDv1字符串（以b开头）
+func (b *CIDBuilder) Sum() string {
+	if len(b.buf) > 0 || len(b.leaves) == 0 {
+		b.flush()
+	}
+	//1. 逐层将节点按174个一组合并为上层节点，直到只剩根节点
+	level := b.leaves
+	for len(level) > 1 {
+		next := []cidNode{}
+		for i := 0; i < len(level); i += CID_MAX_LINKS {
+			end := i + CID_MAX_LINKS
+			if end > len(level) {
+				end = len(level)
+			}
+			next = append(next, fileNode(level[i:end]))
+		}
+		level = next
+	}
+	//2. 单个分块的文件直接使用raw块CID
+	return "b" + strings.ToLower(cidBase32.EncodeToString(level[0].cid))
+}
+
+// 将缓冲区作为一个raw叶子块
+func (b *CIDBuilder) flush() {
+	b.leaves = append(b.leaves, cidNode{
+		cid:      cidV1(codecRaw, b.buf),
+		fileSize: uint64(len(b.buf)),
+		treeSize: uint64(len(b.buf)),
+	})
+	b.buf = b.buf[:0]
+}
+
+// 由子节点构造UnixFS文件节点（dag-pb编码：先Links后Data）
+func fileNode(children []cidNode) cidNode {
+	var node, data []byte
+	var fileSize, treeSize uint64
+	//1. UnixFS Data：Type=File，filesize，各子节点的blocksizes
+	data = appendTag(data, 1, 0)
+	data = binary.AppendUvarint(data, 2)
+	for _, c := range children {
+		fileSize += c.fileSize
+	}
+	data = appendTag(data, 3, 0)
+	data = binary.AppendUvarint(data, fileSize)
+	for _, c := range children {
+		data = appendTag(data, 4, 0)
+		data = binary.AppendUvarint(data, c.fileSize)
+	}
+	//2. PBLink：Hash、Name（空）、Tsize
+	for _, c := range children {
+		var link []byte
+		link = appendBytes(link, 1, c.cid)
+		link = appendBytes(link, 2, nil)
+		link = appendTag(link, 3, 0)
+		link = binary.AppendUvarint(link, c.treeSize)
+		node = appendBytes(node, 2, link)
+		treeSize += c.treeSize
+	}
+	//3. PBNode Data
+	node = appendBytes(node, 1, data)
+	return cidNode{
+		cid:      cidV1(codecDagPB, node),
+		fileSize: fileSize,
+		treeSize: treeSize + uint64(len(node)),
+	}
+}
+
+// CIDv1二进制：版本、编码、sha2-256 multihash
+func cidV1(codec uint64, block []byte) []byte {
+	digest := sha256.Sum256(block)
+	cid := binary.AppendUvarint(nil, 1)
+	cid = binary.AppendUvarint(cid, codec)
+	cid = binary.AppendUvarint(cid, hashSHA2256)
+	cid = binary.AppendUvarint(cid, uint64(len(digest)))
+	return append(cid, digest[:]...)
+}
+
+// protobuf字段标签
+func appendTag(b []byte, field, wireType uint64) []byte {
+	return binary.AppendUvarint(b, field<<3|wireType)
+}
+
+// protobuf长度前缀字段
+func appendBytes(b []byte, field uint64, v []byte) []byte {
+	b = appendTag(b, field, 2)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func cidOf(data []byte, writeSize int) string {
+	b := NewCIDBuilder()
+	for len(data) > writeSize {
+		b.Write(data[:writeSize])
+		data = data[writeSize:]
+	}
+	b.Write(data)
+	return b.Sum()
+}
+
+// 单个分块的文件为raw块CID，与 ipfs add --cid-version=1 的输出一致
+func TestCIDSingleChunk(t *testing.T) {
+	cases := []struct {
+		data string
+		cid  string
+	}{
+		{"", "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+		{"hello world", "bafkreifzjut3te2nhyekklss27nh3k72ysco7y32koao5eei66wof36n5e"},
+	}
+	for _, c := range cases {
+		if got := cidOf([]byte(c.data), 3); got != c.cid {
+			t.Errorf("cid of %q: got %s, want %s", c.data, got, c.cid)
+		}
+	}
+}
+
+// 按dag-pb规范逐字节构造UnixFS文件节点：PBNode先Links(2)后Data(1)，
+// PBLink为Hash(1)、空Name(2)、Tsize(3)，UnixFS Data为Type(1)=File、filesize(3)、blocksizes(4)
+func expectedFileNode(leaves [][]byte) []byte {
+	var node, data []byte
+	var total uint64
+	for _, l := range leaves {
+		total += uint64(len(l))
+	}
+	data = append(data, 0x08, 0x02, 0x18)
+	data = binary.AppendUvarint(data, total)
+	for _, l := range leaves {
+		data = append(data, 0x20)
+		data = binary.AppendUvarint(data, uint64(len(l)))
+	}
+	for _, l := range leaves {
+		digest := sha256.Sum256(l)
+		// CIDv1 raw sha2-256：01 55 12 20 + 摘要
+		link := append([]byte{0x0a, 0x24, 0x01, 0x55, 0x12, 0x20}, digest[:]...)
+		link = append(link, 0x12, 0x00, 0x18)
+		link = binary.AppendUvarint(link, uint64(len(l)))
+		node = append(node, 0x12)
+		node = binary.AppendUvarint(node, uint64(len(link)))
+		node = append(node, link...)
+	}
+	node = append(node, 0x0a)
+	node = binary.AppendUvarint(node, uint64(len(data)))
+	return append(node, data...)
+}
+
+// dag-pb节点的CIDv1（01 70 12 20 + 摘要）
+func dagPBCID(node []byte) string {
+	digest := sha256.Sum256(node)
+	raw := append([]byte{0x01, 0x70, 0x12, 0x20}, digest[:]...)
+	return "b" + strings.ToLower(cidBase32.EncodeToString(raw))
+}
+
+// 多个分块的文件：叶子为256KiB的raw块，根节点为UnixFS文件节点，写入方式不影响结果
+func TestCIDMultiChunk(t *testing.T) {
+	data := make([]byte, 2*CID_CHUNK_SIZE+1000)
+	for i := range data {
+		data[i] = byte(i*7 + i/CID_CHUNK_SIZE)
+	}
+	leaves := [][]byte{data[:CID_CHUNK_SIZE], data[CID_CHUNK_SIZE : 2*CID_CHUNK_SIZE], data[2*CID_CHUNK_SIZE:]}
+	want := dagPBCID(expectedFileNode(leaves))
+	if !strings.HasPrefix(want, "bafybei") {
+		t.Fatalf("unexpected dag-pb cid prefix %s", want)
+	}
+	for _, size := range []int{1000, CID_CHUNK_SIZE, len(data)} {
+		if got := cidOf(data, size); got != want {
+			t.Errorf("write size %d: got %s, want %s", size, got, want)
+		}
+	}
+	// 恰好一个分块时仍为raw块
+	if got := cidOf(data[:CID_CHUNK_SIZE], CID_CHUNK_SIZE); !strings.HasPrefix(got, "bafkrei") {
+		t.Errorf("single full chunk should be a raw block, got %s", got)
+	}
+}
+
+// 超过174个分块时增加一层中间节点
+func TestCIDTwoLevels(t *testing.T) {
+	chunks := CID_MAX_LINKS + 1
+	data := bytes.Repeat([]byte{0x5a}, chunks*CID_CHUNK_SIZE)
+	leaves := make([][]byte, chunks)
+	for i := range leaves {
+		leaves[i] = data[i*CID_CHUNK_SIZE : (i+1)*CID_CHUNK_SIZE]
+	}
+	first := fileNode(leafNodes(leaves[:CID_MAX_LINKS]))
+	second := fileNode(leafNodes(leaves[CID_MAX_LINKS:]))
+	if dagPBCID(expectedFileNode(leaves[:CID_MAX_LINKS])) != "b"+strings.ToLower(cidBase32.EncodeToString(first.cid)) {
+		t.Fatal("first intermediate node does not match the dag-pb layout")
+	}
+	root := fileNode([]cidNode{first, second})
+	if root.fileSize != uint64(len(data)) {
+		t.Fatalf("unexpected root file size %d", root.fileSize)
+	}
+	want := "b" + strings.ToLower(cidBase32.EncodeToString(root.cid))
+	if got := cidOf(data, CID_CHUNK_SIZE); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func leafNodes(leaves [][]byte) []cidNode {
+	nodes := make([]cidNode, len(leaves))
+	for i, l := range leaves {
+		nodes[i] = cidNode{cid: cidV1(codecRaw, l), fileSize: uint64(len(l)), treeSize: uint64(len(l))}
+	}
+	return nodes
+}
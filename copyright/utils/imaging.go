@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// 5x7点阵字形，水印只需要显示token ID（#和数字）
+var glyphs = map[rune][7]uint8{
+	'#': {0b01010, 0b01010, 0b11111, 0b01010, 0b11111, 0b01010, 0b01010},
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11110, 0b00001, 0b00001, 0b01110, 0b00001, 0b00001, 0b11110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+}
+
+// 水印透明度（0~255）
+const (
+	watermarkAlpha = 110
+	shadowAlpha    = 70
+)
+
+// 图片文件头声明的尺寸超过像素上限
+var ErrImageTooLarge = errors.New("image dimensions exceed the pixel limit")
+
+// 解码图片，解码前先读取文件头声明的宽高，像素数超过maxPixels时不解码，
+// 防止很小的文件声明超大尺寸耗尽内存；maxPixels不大于0时不限制
+func DecodeImage(r io.Reader, maxPixels int64) (image.Image, string, error) {
+	//1. 读取文件头时保留已读取的数据，解码时重新拼接
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, "", err
+	}
+	if maxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+	//2. 完整解码
+	return image.Decode(io.MultiReader(&header, r))
+}
+
+// 按最长边不超过maxSide等比缩小（区域平均），不放大
+func Resize(src image.Image, maxSide int) *image.RGBA {
+	//1. 转为RGBA以便直接访问像素，原图已是RGBA时直接采样，避免再复制一份整图
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) || (w <= maxSide && h <= maxSide) {
+		rgba = image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	if w <= maxSide && h <= maxSide {
+		return rgba
+	}
+	//2. 计算目标尺寸
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	//3. 每个目标像素取对应源区域的平均值
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, (y+1)*h/dh
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, (x+1)*w/dw
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				i := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(rgba.Pix[i])
+					g += uint32(rgba.Pix[i+1])
+					bl += uint32(rgba.Pix[i+2])
+					a += uint32(rgba.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(bl / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// 在图片上平铺半透明文字水印，相邻行错开半个文字宽度，裁剪后仍能看到水印
+func Watermark(img *image.RGBA, text string) {
+	b := img.Bounds()
+	//1. 字号使一行文字约占图片宽度的一半
+	cols := len(text) * 6
+	scale := b.Dx() / (cols * 2)
+	if scale < 1 {
+		scale = 1
+	}
+	tw, th := cols*scale, 7*scale
+	//2. 逐行平铺
+	for row, y := 0, th; y < b.Dy(); row, y = row+1, y+th*4 {
+		x := -tw / 2 * (row % 2)
+		for ; x < b.Dx(); x += tw + tw/2 {
+			drawText(img, text, x+scale/2+1, y+scale/2+1, scale, 0, shadowAlpha)
+			drawText(img, text, x, y, scale, 255, watermarkAlpha)
+		}
+	}
+}
+
+// 以灰度值gray、透明度alpha绘制点阵文字，(x, y)为左上角
+func drawText(img *image.RGBA, text string, x, y, scale int, gray, alpha uint8) {
+	for _, ch := range text {
+		glyph, ok := glyphs[ch]
+		if ok {
+			for gy, bits := range glyph {
+				for gx := 0; gx < 5; gx++ {
+					if bits&(1<<(4-gx)) != 0 {
+						blendRect(img, x+gx*scale, y+gy*scale, scale, gray, alpha)
+					}
+				}
+			}
+		}
+		x += 6 * scale
+	}
+}
+
+// 将边长为size的方块与gray按alpha混合
+func blendRect(img *image.RGBA, x, y, size int, gray, alpha uint8) {
+	r := image.Rect(x, y, x+size, y+size).Intersect(img.Bounds())
+	a := uint32(alpha)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		i := img.PixOffset(r.Min.X, py)
+		for px := r.Min.X; px < r.Max.X; px++ {
+			for k := 0; k < 3; k++ {
+				img.Pix[i+k] = uint8((uint32(img.Pix[i+k])*(255-a) + uint32(gray)*a) / 255)
+			}
+			i += 4
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeImageRejectsDeclaredSize(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 200, 100))
+	data := encodePNG(t, img)
+	// 200x100=20000像素，上限以内正常解码
+	decoded, format, err := DecodeImage(bytes.NewReader(data), 20000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "png" || decoded.Bounds().Dx() != 200 || decoded.Bounds().Dy() != 100 {
+		t.Fatalf("unexpected decode result %s %v", format, decoded.Bounds())
+	}
+	// 超出上限时在完整解码前拒绝
+	if _, _, err = DecodeImage(bytes.NewReader(data), 19999); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("expected ErrImageTooLarge, got %v", err)
+	}
+}
+
+func TestDecodeImageForgedHeader(t *testing.T) {
+	// 修改IHDR中的宽高为65535x65535，文件本身只有几十字节
+	data := encodePNG(t, image.NewGray(image.Rect(0, 0, 1, 1)))
+	forged := append([]byte(nil), data...)
+	copy(forged[16:24], []byte{0, 0, 0xff, 0xff, 0, 0, 0xff, 0xff})
+	binary.BigEndian.PutUint32(forged[29:33], crc32.ChecksumIEEE(forged[12:29]))
+	if _, _, err := DecodeImage(bytes.NewReader(forged), 40000000); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("expected ErrImageTooLarge, got %v", err)
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			c := color.RGBA{A: 255}
+			if x >= 200 {
+				c.R = 255
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+	dst := Resize(src, 100)
+	if dst.Bounds().Dx() != 100 || dst.Bounds().Dy() != 50 {
+		t.Fatalf("unexpected size %v", dst.Bounds())
+	}
+	if dst.RGBAAt(10, 10).R != 0 || dst.RGBAAt(90, 10).R != 255 {
+		t.Fatalf("unexpected pixels %v %v", dst.RGBAAt(10, 10), dst.RGBAAt(90, 10))
+	}
+	// 不放大，且返回的是副本
+	small := Resize(dst, 320)
+	if small == dst || small.Bounds() != dst.Bounds() {
+		t.Fatal("expected a copy of the same size")
+	}
+}
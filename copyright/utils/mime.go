@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 识别MIME类型时读取的文件头长度，与http.DetectContentType一致
+const SNIFF_LEN = 512
+
+// 各MIME类型允许保留的原始扩展名，第一个为缺省扩展名
+var mimeExts = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg", ".jpe"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"image/bmp":       {".bmp"},
+	"audio/mpeg":      {".mp3"},
+	"audio/wave":      {".wav"},
+	"audio/aiff":      {".aiff", ".aif"},
+	"audio/midi":      {".mid", ".midi"},
+	"application/ogg": {".ogg", ".oga", ".ogv", ".opus"},
+	"video/mp4":       {".mp4", ".m4v", ".m4a"},
+	"video/webm":      {".webm"},
+	"video/avi":       {".avi"},
+	"application/pdf": {".pdf"},
+	"text/plain":      {".txt", ".md", ".csv", ".log"},
+}
+
+var extPattern = regexp.MustCompile(`^\.[a-z0-9]{1,10}$`)
+
+// 按文件头内容识别MIME类型（不含charset等参数），与客户端声明的类型和文件名无关
+func SniffMIME(head []byte) string {
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mimeType
+}
+
+// 按保存文件的扩展名返回Content-Type，未知扩展名返回application/octet-stream
+func MIMEByExt(ext string) string {
+	ext = strings.ToLower(ext)
+	for mimeType, exts := range mimeExts {
+		for _, e := range exts {
+			if e == ext {
+				if strings.HasPrefix(mimeType, "text/") {
+					return mimeType + "; charset=utf-8"
+				}
+				return mimeType
+			}
+		}
+	}
+	return "application/octet-stream"
+}
+
+// 保存文件使用的扩展名：原始扩展名与识别出的类型一致时保留，否则使用该类型的缺省扩展名，
+// 避免以.html等扩展名保存后被静态文件服务按其他类型返回
+func FileExt(filename, mimeType string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	exts := mimeExts[mimeType]
+	for _, e := range exts {
+		if e == ext {
+			return ext
+		}
+	}
+	if len(exts) > 0 {
+		return exts[0]
+	}
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 && extPattern.MatchString(exts[0]) {
+		return exts[0]
+	}
+	return ".bin"
+}
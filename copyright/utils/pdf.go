@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf16"
+)
+
+// A4页面尺寸（pt）
+const (
+	PDF_PAGE_WIDTH  = 595.28
+	PDF_PAGE_HEIGHT = 841.89
+)
+
+// 最简PDF生成：A4多页，文字使用Adobe中文字体STSong-Light（阅读器内置，无需嵌入），
+// UniGB-UTF16-H编码同时支持中文和ASCII；另支持直线和填充矩形，坐标原点在页面左下角
+type PDF struct {
+	pages []*bytes.Buffer
+}
+
+func NewPDF() *PDF {
+	p := &PDF{}
+	p.AddPage()
+	return p
+}
+
+// 新增一页，之后的绘制都在该页上
+func (p *PDF) AddPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+}
+
+func (p *PDF) page() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// 在(x, y)处绘制文字，y为基线位置
+func (p *PDF) Text(x, y, size float64, text string) {
+	var hex bytes.Buffer
+	for _, u := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&hex, "%04X", u)
+	}
+	fmt.Fprintf(p.page(), "BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, y, hex.String())
+}
+
+// 文字宽度：ASCII半角，其余全角
+func (p *PDF) TextWidth(size float64, text string) float64 {
+	w := 0.0
+	for _, ch := range text {
+		if ch < 0x80 {
+			w += size / 2
+		} else {
+			w += size
+		}
+	}
+	return w
+}
+
+// 按宽度折行
+func (p *PDF) SplitText(size, width float64, text string) []string {
+	lines := []string{}
+	line, w := []rune{}, 0.0
+	for _, ch := range text {
+		cw := p.TextWidth(size, string(ch))
+		if w+cw > width && len(line) > 0 {
+			lines = append(lines, string(line))
+			line, w = line[:0], 0
+		}
+		line = append(line, ch)
+		w += cw
+	}
+	return append(lines, string(line))
+}
+
+// 设置填充和描边灰度（0为黑色，1为白色）
+func (p *PDF) SetGray(gray float64) {
+	fmt.Fprintf(p.page(), "%.3f g %.3f G\n", gray, gray)
+}
+
+// 绘制直线
+func (p *PDF) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(p.page(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// 绘制矩形边框
+func (p *PDF) StrokeRect(x, y, w, h, width float64) {
+	fmt.Fprintf(p.page(), "%.2f w %.2f %.2f %.2f %.2f re S\n", width, x, y, w, h)
+}
+
+// 绘制填充矩形，(x, y)为左下角
+func (p *PDF) FillRect(x, y, w, h float64) {
+	fmt.Fprintf(p.page(), "%.2f %.2f %.2f %.2f re f\n", x, y, w, h)
+}
+
+// 输出PDF文件内容
+func (p *PDF) Bytes() []byte {
+	//1. 对象编号：1 Catalog，2 Pages，3~5字体，之后每页一个Page和一个内容流
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UTF16-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> " +
+			"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+			"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+	}
+	kids := &bytes.Buffer{}
+	for _, content := range p.pages {
+		pageID := len(objs) + 1
+		fmt.Fprintf(kids, "%d 0 R ", pageID)
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				PDF_PAGE_WIDTH, PDF_PAGE_HEIGHT, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+	objs[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(p.pages))
+	//2. 依次写出对象并记录偏移
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	//3. 交叉引用表和文件尾
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return out.Bytes()
+}
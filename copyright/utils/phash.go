@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math/bits"
+	"strconv"
+)
+
+// dHash缩略图尺寸：9列8行灰度，相邻列比较得到64位哈希
+const (
+	DHASH_WIDTH  = 9
+	DHASH_HEIGHT = 8
+)
+
+// 每个缩略格最多采样的像素数（每边），大图按步长抽样
+const dhashSamples = 16
+
+// 计算图片的差异哈希（dHash），缩放、重新压缩和轻微编辑后哈希基本不变
+// 返回16位十六进制字符串，无法解码或像素数超过maxPixels的内容返回错误
+func DHash(r io.Reader, maxPixels int64) (string, error) {
+	img, _, err := DecodeImage(r, maxPixels)
+	if err != nil {
+		return "", err
+	}
+	//1. 缩小为9x8灰度图，每格取区域内采样像素的平均亮度
+	b := img.Bounds()
+	if b.Dx() < DHASH_WIDTH || b.Dy() < DHASH_HEIGHT {
+		return "", fmt.Errorf("image too small: %dx%d", b.Dx(), b.Dy())
+	}
+	var gray [DHASH_HEIGHT][DHASH_WIDTH]float64
+	for row := 0; row < DHASH_HEIGHT; row++ {
+		y0 := b.Min.Y + row*b.Dy()/DHASH_HEIGHT
+		y1 := b.Min.Y + (row+1)*b.Dy()/DHASH_HEIGHT
+		for col := 0; col < DHASH_WIDTH; col++ {
+			x0 := b.Min.X + col*b.Dx()/DHASH_WIDTH
+			x1 := b.Min.X + (col+1)*b.Dx()/DHASH_WIDTH
+			gray[row][col] = averageLuma(img, x0, y0, x1, y1)
+		}
+	}
+	//2. 每行相邻两格比较，左侧更亮记为1
+	var hash uint64
+	for row := 0; row < DHASH_HEIGHT; row++ {
+		for col := 0; col < DHASH_WIDTH-1; col++ {
+			hash <<= 1
+			if gray[row][col] > gray[row][col+1] {
+				hash |= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%016x", hash), nil
+}
+
+// 区域[x0,x1)x[y0,y1)内抽样像素的平均亮度（ITU-R BT.601）
+func averageLuma(img image.Image, x0, y0, x1, y1 int) float64 {
+	stepX := (x1-x0)/dhashSamples + 1
+	stepY := (y1-y0)/dhashSamples + 1
+	var sum float64
+	var n int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			n++
+		}
+	}
+	return sum / float64(n)
+}
+
+// 两个dHash的相似度，取值0~1，1表示完全一致
+func HashSimilarity(a, b string) (float64, error) {
+	x, err := strconv.ParseUint(a, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	y, err := strconv.ParseUint(b, 16, 64)
+	if err != nil {
+		return 0, err
+	}
+	return 1 - float64(bits.OnesCount64(x^y))/64, nil
+}
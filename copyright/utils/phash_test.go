@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// 水平渐变图，ascending为true时从左到右变亮
+func gradient(w, h int, ascending bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := x * 255 / (w - 1)
+			if !ascending {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(v)})
+		}
+	}
+	return img
+}
+
+func dhashOf(t *testing.T, img image.Image) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := DHash(&buf, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+// 每行左侧更亮记为1：从左到右变亮的图全为0，变暗的图全为1
+func TestDHashGradient(t *testing.T) {
+	if got := dhashOf(t, gradient(180, 120, true)); got != "0000000000000000" {
+		t.Errorf("ascending gradient: got %s", got)
+	}
+	if got := dhashOf(t, gradient(180, 120, false)); got != "ffffffffffffffff" {
+		t.Errorf("descending gradient: got %s", got)
+	}
+}
+
+// 缩放和重新压缩后哈希基本不变
+func TestDHashResized(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x * y % 251), G: uint8((x/40 + y/30) * 17), B: uint8(x / 3), A: 255})
+		}
+	}
+	original := dhashOf(t, src)
+	resized := dhashOf(t, Resize(src, 200))
+	similarity, err := HashSimilarity(original, resized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if similarity < 0.9 {
+		t.Errorf("similarity of resized image %.3f, hashes %s %s", similarity, original, resized)
+	}
+}
+
+func TestDHashTooSmall(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, gradient(8, 8, true), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DHash(&buf, 0); err == nil {
+		t.Error("expected error for image smaller than 9x8")
+	}
+}
+
+func TestHashSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"0000000000000000", "0000000000000000", 1},
+		{"0000000000000000", "ffffffffffffffff", 0},
+		{"00000000000000ff", "0000000000000000", 0.875},
+	}
+	for _, c := range cases {
+		got, err := HashSimilarity(c.a, c.b)
+		if err != nil || got != c.want {
+			t.Errorf("HashSimilarity(%s, %s) = %v, %v; want %v", c.a, c.b, got, err, c.want)
+		}
+	}
+	if _, err := HashSimilarity("xyz", "0"); err == nil {
+		t.Error("expected error for invalid hash")
+	}
+}
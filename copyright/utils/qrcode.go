@@ -0,0 +1,444 @@
+package utils
+
+import "errors"
+
+// QR码参数：纠错等级M，字节模式，版本1~10（最多213字节），足够容纳验证链接
+const (
+	QR_MAX_VERSION = 10
+	qrFormatMask   = 0x5412
+	qrFormatPoly   = 0x537
+	qrVersionPoly  = 0x1F25
+	qrLevelM       = 0 //纠错等级M的格式信息编码
+)
+
+// 各版本纠错等级M的分块参数
+type qrVersion struct {
+	ecLen  int      //每块纠错码字数
+	blocks [][2]int //{块数, 每块数据码字数}
+	align  []int    //校正图形中心坐标
+}
+
+var qrVersions = [QR_MAX_VERSION + 1]qrVersion{
+	1:  {10, [][2]int{{1, 16}}, nil},
+	2:  {16, [][2]int{{1, 28}}, []int{6, 18}},
+	3:  {26, [][2]int{{1, 44}}, []int{6, 22}},
+	4:  {18, [][2]int{{2, 32}}, []int{6, 26}},
+	5:  {24, [][2]int{{2, 43}}, []int{6, 30}},
+	6:  {16, [][2]int{{4, 27}}, []int{6, 34}},
+	7:  {18, [][2]int{{4, 31}}, []int{6, 22, 38}},
+	8:  {22, [][2]int{{2, 38}, {2, 39}}, []int{6, 24, 42}},
+	9:  {22, [][2]int{{3, 36}, {2, 37}}, []int{6, 26, 46}},
+	10: {26, [][2]int{{4, 43}, {1, 44}}, []int{6, 28, 50}},
+}
+
+// 数据码字总数
+func (v qrVersion) dataLen() int {
+	n := 0
+	for _, b := range v.blocks {
+		n += b[0] * b[1]
+	}
+	return n
+}
+
+// QR码模块矩阵
+type QRCode struct {
+	Size     int
+	modules  [][]bool
+	function [][]bool //功能图形区域，不放置数据、不掩模
+}
+
+// 将文本编码为QR码
+func NewQRCode(text string) (*QRCode, error) {
+	//1. 选择能容纳数据的最小版本
+	data := []byte(text)
+	version := 0
+	for v := 1; v <= QR_MAX_VERSION; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(data)*8 <= qrVersions[v].dataLen()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("text too long for qr code")
+	}
+	ver := qrVersions[version]
+	//2. 字节模式编码并填充到数据码字总数
+	var bits qrBits
+	bits.append(0b0100, 4)
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := ver.dataLen() * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	codewords := bits.bytes()
+	for pad := 0; len(codewords) < ver.dataLen(); pad++ {
+		codewords = append(codewords, []byte{0xEC, 0x11}[pad%2])
+	}
+	//3. 分块计算纠错码并交错排列
+	codewords = qrInterleave(codewords, ver)
+	//4. 绘制功能图形、放置数据，选择惩罚分最低的掩模
+	q := &QRCode{Size: version*4 + 17}
+	q.modules = newGrid(q.Size)
+	q.function = newGrid(q.Size)
+	q.drawFunctions(version)
+	q.drawCodewords(codewords)
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormat(best)
+	return q, nil
+}
+
+// 模块(x, y)是否为深色，x为列、y为行
+func (q *QRCode) Dark(x, y int) bool {
+	return q.modules[y][x]
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+// 设置功能图形模块
+func (q *QRCode) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+// 绘制定位图形、分隔符、定时图形、校正图形、版本信息，并预留格式信息区域
+func (q *QRCode) drawFunctions(version int) {
+	size := q.Size
+	//1. 定时图形
+	for i := 0; i < size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+	//2. 三个定位图形及分隔符
+	for _, c := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || x >= size || y < 0 || y >= size {
+					continue
+				}
+				d := max(abs(dx), abs(dy))
+				q.setFunction(x, y, d != 2 && d != 4)
+			}
+		}
+	}
+	//3. 校正图形，与定位图形重叠的位置除外
+	align := qrVersions[version].align
+	for i, cy := range align {
+		for j, cx := range align {
+			if (i == 0 && j == 0) || (i == 0 && j == len(align)-1) || (i == len(align)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	//4. 预留格式信息区域（含固定的深色模块）
+	q.drawFormat(0)
+	//5. 版本7及以上的版本信息
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*qrVersionPoly
+		}
+		info := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := info>>i&1 == 1
+			a, b := size-11+i%3, i/3
+			q.setFunction(a, b, dark)
+			q.setFunction(b, a, dark)
+		}
+	}
+}
+
+// 绘制两份格式信息（纠错等级和掩模）
+func (q *QRCode) drawFormat(mask int) {
+	data := qrLevelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*qrFormatPoly
+	}
+	info := (data<<10 | rem) ^ qrFormatMask
+	bit := func(i int) bool { return info>>i&1 == 1 }
+	size := q.Size
+	//1. 左上角
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+	//2. 右上角和左下角
+	for i := 0; i < 8; i++ {
+		q.setFunction(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, size-15+i, bit(i))
+	}
+	q.setFunction(8, size-8, true)
+}
+
+// 按之字形从右下角开始放置数据位，跳过功能图形和第6列
+func (q *QRCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.Size; vert++ {
+			y := vert
+			if upward {
+				y = q.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if q.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				q.modules[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// 对数据区域应用掩模，再次应用即可撤销
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// 按标准的四条规则计算掩模惩罚分
+func (q *QRCode) penalty() int {
+	size, p, dark := q.Size, 0, 0
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+	finder := []bool{true, false, true, true, true, false, true}
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < size; y++ {
+			//1. 行（列）中连续5个及以上同色模块
+			run := 1
+			for x := 1; x < size; x++ {
+				if at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				p += run - 2
+			}
+			//3. 一侧有4个浅色模块的1:1:3:1:1图形
+			for x := 0; x+7 <= size; x++ {
+				match := true
+				for k, d := range finder {
+					if at(x+k, y, transpose) != d {
+						match = false
+						break
+					}
+				}
+				if !match {
+					continue
+				}
+				if x >= 4 && !at(x-1, y, transpose) && !at(x-2, y, transpose) && !at(x-3, y, transpose) && !at(x-4, y, transpose) {
+					p += 40
+				}
+				if x+11 <= size && !at(x+7, y, transpose) && !at(x+8, y, transpose) && !at(x+9, y, transpose) && !at(x+10, y, transpose) {
+					p += 40
+				}
+			}
+		}
+	}
+	//2. 2x2同色块
+	for y := 0; y+1 < size; y++ {
+		for x := 0; x+1 < size; x++ {
+			c := q.modules[y][x]
+			if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				p += 3
+			}
+		}
+	}
+	//4. 深色模块比例偏离50%
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+		}
+	}
+	total := size * size
+	p += abs(dark*20-total*10) / total * 10
+	return p
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// 按位追加的缓冲区
+type qrBits []bool
+
+func (b *qrBits) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1 == 1)
+	}
+}
+
+func (b qrBits) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+// 分块计算Reed-Solomon纠错码，按列交错排列数据码字和纠错码字
+func qrInterleave(data []byte, ver qrVersion) []byte {
+	gen := rsGenerator(ver.ecLen)
+	var dataBlocks, ecBlocks [][]byte
+	for _, b := range ver.blocks {
+		for i := 0; i < b[0]; i++ {
+			block := data[:b[1]]
+			data = data[b[1]:]
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, rsRemainder(block, gen))
+		}
+	}
+	var out []byte
+	maxLen := len(dataBlocks[len(dataBlocks)-1])
+	for i := 0; i < maxLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < ver.ecLen; i++ {
+		for _, block := range ecBlocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+// GF(256)乘法，本原多项式x^8+x^4+x^3+x^2+1
+func gfMul(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		hi := a & 0x80
+		a <<= 1
+		if hi != 0 {
+			a ^= 0x1D
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// 生成多项式(x-α^0)(x-α^1)...(x-α^(n-1))的系数，省略最高次项
+func rsGenerator(n int) []byte {
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			gen[j] = gfMul(gen[j], root)
+			if j+1 < n {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return gen
+}
+
+// 数据多项式除以生成多项式的余数，即纠错码字
+func rsRemainder(data, gen []byte) []byte {
+	rem := make([]byte, len(gen))
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[len(rem)-1] = 0
+		for i := range rem {
+			rem[i] ^= gfMul(gen[i], factor)
+		}
+	}
+	return rem
+}
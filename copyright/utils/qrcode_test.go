@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Reed-Solomon纠错码与公开的1-M示例一致
+func TestQRReedSolomon(t *testing.T) {
+	cases := []struct {
+		name     string
+		data, ec []byte
+	}{
+		{
+			// ISO/IEC 18004 附录中的 "01234567"（数字模式）
+			name: "01234567",
+			data: []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			ec:   []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+		{
+			// "HELLO WORLD"（字母数字模式）
+			name: "HELLO WORLD",
+			data: []byte{0x20, 0x5B, 0x0B, 0x78, 0xD1, 0x72, 0xDC, 0x4D, 0x43, 0x40, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			ec:   []byte{0xC4, 0x23, 0x27, 0x77, 0xEB, 0xD7, 0xE7, 0xE2, 0x5D, 0x17},
+		},
+	}
+	for _, c := range cases {
+		if got := rsRemainder(c.data, rsGenerator(len(c.ec))); !bytes.Equal(got, c.ec) {
+			t.Errorf("%s: got % X, want % X", c.name, got, c.ec)
+		}
+	}
+}
+
+// 纠错等级M各掩模的格式信息（15位，含BCH校验并异或0x5412）
+var qrFormatM = [8]string{
+	"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+	"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+}
+
+// 读取左上角的格式信息，返回最高位在前的15位字符串
+func readFormat(q *QRCode) string {
+	var bits [15]bool
+	for i := 0; i <= 5; i++ {
+		bits[i] = q.Dark(8, i)
+	}
+	bits[6] = q.Dark(8, 7)
+	bits[7] = q.Dark(8, 8)
+	bits[8] = q.Dark(7, 8)
+	for i := 9; i < 15; i++ {
+		bits[i] = q.Dark(14-i, 8)
+	}
+	var s strings.Builder
+	for i := 14; i >= 0; i-- {
+		if bits[i] {
+			s.WriteByte('1')
+		} else {
+			s.WriteByte('0')
+		}
+	}
+	return s.String()
+}
+
+// 按放置顺序读回全部码字
+func readCodewords(q *QRCode) []byte {
+	var out []byte
+	var cur byte
+	n := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.Size; vert++ {
+			y := vert
+			if upward {
+				y = q.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if q.function[y][x] {
+					continue
+				}
+				cur <<= 1
+				if q.modules[y][x] {
+					cur |= 1
+				}
+				if n++; n%8 == 0 {
+					out = append(out, cur)
+					cur = 0
+				}
+			}
+		}
+	}
+	return out
+}
+
+// 编码后按标准流程解码：读取格式信息、撤销掩模、反交错并校验纠错码，得到原文
+func TestQRCodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"https://copyright.example.com/content/1/verify",
+		"https://copyright.example.com/content/115792089237316195423570985008687907853269984665640564039457584007913129639935/verify",
+		strings.Repeat("a", 213),
+	} {
+		q, err := NewQRCode(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version := (q.Size - 17) / 4
+		ver := qrVersions[version]
+		//1. 定位图形
+		for _, p := range [][2]int{{0, 0}, {q.Size - 7, 0}, {0, q.Size - 7}} {
+			if !q.Dark(p[0], p[1]) || !q.Dark(p[0]+3, p[1]+3) || q.Dark(p[0]+1, p[1]+1) {
+				t.Fatalf("finder pattern at %v is broken", p)
+			}
+		}
+		//2. 格式信息
+		format := readFormat(q)
+		mask := -1
+		for m, f := range qrFormatM {
+			if f == format {
+				mask = m
+			}
+		}
+		if mask < 0 {
+			t.Fatalf("invalid format info %s", format)
+		}
+		//3. 撤销掩模后读回码字，反交错
+		q.applyMask(mask)
+		codewords := readCodewords(q)
+		q.applyMask(mask)
+		var blockLens []int
+		for _, b := range ver.blocks {
+			for i := 0; i < b[0]; i++ {
+				blockLens = append(blockLens, b[1])
+			}
+		}
+		blocks := make([][]byte, len(blockLens))
+		pos := 0
+		for i := 0; i < blockLens[len(blockLens)-1]; i++ {
+			for b, l := range blockLens {
+				if i < l {
+					blocks[b] = append(blocks[b], codewords[pos])
+					pos++
+				}
+			}
+		}
+		for i := 0; i < ver.ecLen; i++ {
+			for b := range blocks {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+		//4. 每块数据码字的纠错码须与读回的一致
+		var data []byte
+		for b, block := range blocks {
+			l := blockLens[b]
+			if ec := rsRemainder(block[:l], rsGenerator(ver.ecLen)); !bytes.Equal(ec, block[l:]) {
+				t.Fatalf("block %d has invalid error correction", b)
+			}
+			data = append(data, block[:l]...)
+		}
+		//5. 字节模式：4位模式指示符、字符计数、数据
+		var bits qrBits
+		for _, b := range data {
+			bits.append(int(b), 8)
+		}
+		read := func(n int) int {
+			v := 0
+			for i := 0; i < n; i++ {
+				v <<= 1
+				if bits[i] {
+					v |= 1
+				}
+			}
+			bits = bits[n:]
+			return v
+		}
+		if mode := read(4); mode != 0b0100 {
+			t.Fatalf("unexpected mode %b", mode)
+		}
+		countBits := 8
+		if version >= 10 {
+			countBits = 16
+		}
+		length := read(countBits)
+		decoded := make([]byte, length)
+		for i := range decoded {
+			decoded[i] = byte(read(8))
+		}
+		if string(decoded) != text {
+			t.Fatalf("decoded %q, want %q", decoded, text)
+		}
+	}
+}
+
+func TestQRCodeTooLong(t *testing.T) {
+	if _, err := NewQRCode(strings.Repeat("a", 214)); err == nil {
+		t.Error("expected error for text longer than version 10 capacity")
+	}
+}
@@ -1,25 +1,33 @@
 package utils
 
 const (
-	RECODE_OK        = "0"
-	RECODE_DBERR     = "4001"
-	RECODE_LOGINERR  = "4002"
-	RECODE_PARAMERR  = "4003"
-	RECODE_SYSERR    = "4004"
-	RECODE_ETHERR    = "4105"
-	RECODE_UNKNOWERR = "4106"
-	RECODE_REPEATERR = "4107"
+	RECODE_OK            = "0"
+	RECODE_DBERR         = "4001"
+	RECODE_LOGINERR      = "4002"
+	RECODE_PARAMERR      = "4003"
+	RECODE_SYSERR        = "4004"
+	RECODE_ETHERR        = "4105"
+	RECODE_UNKNOWERR     = "4106"
+	RECODE_REPEATERR     = "4107"
+	RECODE_INPROGRESSERR = "4108"
+	RECODE_DUPLICATEERR  = "4109"
+	RECODE_FILETYPEERR   = "4110"
+	RECODE_FILESIZEERR   = "4111"
 )
 
 var recodeText = map[string]string{
-	RECODE_OK:        "成功",
-	RECODE_DBERR:     "数据库操作错误",
-	RECODE_LOGINERR:  "用户登录失败",
-	RECODE_PARAMERR:  "参数错误",
-	RECODE_SYSERR:    "系统错误",
-	RECODE_ETHERR:    "与以太坊交互失败",
-	RECODE_UNKNOWERR: "未知错误",
-	RECODE_REPEATERR: "不允许出售重复资产，请下架后再试",
+	RECODE_OK:            "成功",
+	RECODE_DBERR:         "数据库操作错误",
+	RECODE_LOGINERR:      "用户登录失败",
+	RECODE_PARAMERR:      "参数错误",
+	RECODE_SYSERR:        "系统错误",
+	RECODE_ETHERR:        "与以太坊交互失败",
+	RECODE_UNKNOWERR:     "未知错误",
+	RECODE_REPEATERR:     "不允许出售重复资产，请下架后再试",
+	RECODE_INPROGRESSERR: "请求正在处理中，请勿重复提交",
+	RECODE_DUPLICATEERR:  "该内容已登记版权，不允许重复上传",
+	RECODE_FILETYPEERR:   "不支持的文件类型",
+	RECODE_FILESIZEERR:   "文件大小超出限制",
 }
 
 func RecodeText(code string) string {
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.17;
+
+/**
+ * @dev 结算合约依赖的ERC20接口
+ */
+interface IPXC20 {
+    function transferFrom(address from, address to, uint256 value) external returns (bool);
+}
+
+/**
+ * @dev 结算合约依赖的ERC721份额转让接口
+ */
+interface IPXA721 {
+    function partTransferFrom(address from, address to, uint256 orgtokenId, uint256 weight, uint256 price) external;
+}
+
+/**
+ * @title Settlement
+ * @dev 份额交易结算合约：在同一笔交易中完成PXC付款和份额转移，任一步失败则整体回滚
+ */
+contract Settlement {
+
+    IPXC20 public pxc;
+    IPXA721 public pxa;
+
+    // 平台结算账户，只有它可以发起结算
+    address public operator;
+
+    event Settled(
+        address indexed buyer,
+        address indexed seller,
+        uint256 indexed tokenId,
+        uint256 weight,
+        uint256 price,
+        address creator,
+        uint256 royalty
+    );
+
+    constructor(address _pxc, address _pxa, address _operator) {
+        require(_operator != address(0), "Settlement: operator is the zero address");
+        pxc = IPXC20(_pxc);
+        pxa = IPXA721(_pxa);
+        operator = _operator;
+    }
+
+    modifier onlyOperator() {
+        require(msg.sender == operator, "Settlement: caller is not the operator");
+        _;
+    }
+
+    /**
+     * @dev 结算一笔份额交易
+     * 买家需事先授权本合约划转不少于 weight * price 的PXC；
+     * 货款扣除版税后付给卖家，版税付给创作者，最后将份额转给买家
+     */
+    function settle(
+        address buyer,
+        address seller,
+        uint256 tokenId,
+        uint256 weight,
+        uint256 price,
+        address creator,
+        uint256 royalty
+    ) external onlyOperator {
+        uint256 total = weight * price;
+        require(royalty <= total, "Settlement: royalty exceeds total");
+
+        require(pxc.transferFrom(buyer, seller, total - royalty), "Settlement: payment failed");
+        if (royalty > 0) {
+            require(pxc.transferFrom(buyer, creator, royalty), "Settlement: royalty payment failed");
+        }
+        pxa.partTransferFrom(seller, buyer, tokenId, weight, price);
+
+        emit Settled(buyer, seller, tokenId, weight, price, creator, royalty);
+    }
+}
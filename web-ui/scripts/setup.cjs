@@ -31,12 +31,22 @@ async function main() {
   const txReceipt721 = await erc721.deploymentTransaction().wait();
   console.log("ERC721 deployed to:", txReceipt721.contractAddress);
   
+  // 部署结算合约，由管理员账户发起结算
+  console.log("\nDeploying Settlement contract...");
+  const admin = "0xCE92C80928B5A0cB2d720fA58296519a9ED7e354";
+  const Settlement = await hre.ethers.getContractFactory("Settlement");
+  const settlement = await Settlement.deploy(txReceipt.contractAddress, txReceipt721.contractAddress, admin);
+  
+  // 等待交易确认并获取合约地址
+  const txReceiptSettle = await settlement.deploymentTransaction().wait();
+  console.log("Settlement deployed to:", txReceiptSettle.contractAddress);
+  
   // ===== 合约部署完成，开始执行转账操作 =====
   console.log("\n===== 开始执行以太币转账操作 =====");
   
   // 给管理员转账以太币
   const from = deployer.address; // 使用部署合约的账户作为转账发起方
-  const to = "0xCE92C80928B5A0cB2d720fA58296519a9ED7e354";
+  const to = admin;
   
   // 解析以太币数量
   const amount = hre.ethers.parseEther("1000");
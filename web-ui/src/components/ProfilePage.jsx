@@ -163,7 +163,7 @@ function ProfilePage() {
       const response = await fetchAPI('/auction', 'POST', {
           token_id: selectedAsset.token_id,
           weight: percentage,
-          price: price
+          price: String(sellPrice).trim()
         });
       
       if (response && response.errno === '0') {
@@ -222,6 +222,19 @@ function ProfilePage() {
         setPurchaseDialogOpen(false);
         // 刷新拍卖资产列表
         fetchAuctionAssets();
+      } else if (response?.errno === '4003' && response.data?.spender) {
+        // 结算合约授权额度不足，需先授权结算合约划转本次总价
+        const { spender, required } = response.data;
+        if (!window.confirm(`需要授权结算合约划转 ${required} PXC，是否授权？`)) {
+          setPurchaseError('授权额度不足，无法购买');
+          return;
+        }
+        const approval = await fetchAPI('/token/approve', 'POST', { spender, value: required });
+        if (approval && approval.errno === '0') {
+          setPurchaseError('授权交易已提交，请在交易确认后再次购买');
+        } else {
+          setPurchaseError(approval?.msg || '授权失败，请重试');
+        }
       } else {
         setPurchaseError(response?.msg || '购买失败，请重试');
       }
@@ -464,7 +477,7 @@ function ProfilePage() {
             fontWeight: '500'
           }}
         >
-          <input type="file" accept="image/*" onChange={handleUploadImage} style={{ display: 'none' }} />
+          <input type="file" accept="image/*,audio/*,video/*,application/pdf,text/plain" onChange={handleUploadImage} style={{ display: 'none' }} />
           上传图片
         </label>
       </Box>